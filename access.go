@@ -0,0 +1,35 @@
+package runtimeconfig
+
+// markAccessed records that key was read
+func (rconfig *RuntimeConfig) markAccessed(key string) {
+	rconfig.accessMu.Lock()
+	defer rconfig.accessMu.Unlock()
+	if rconfig.accessed == nil {
+		rconfig.accessed = make(map[string]bool)
+	}
+	rconfig.accessed[key] = true
+}
+
+// UnaccessedKeys returns the sorted keys that have not been read through
+// Get or the typed getters since creation or the last ResetAccessTracking
+func (rconfig *RuntimeConfig) UnaccessedKeys() []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	rconfig.accessMu.Lock()
+	defer rconfig.accessMu.Unlock()
+
+	var keys []string
+	for _, key := range sortedKeys(rconfig.data) {
+		if !rconfig.accessed[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ResetAccessTracking forgets which keys have been read
+func (rconfig *RuntimeConfig) ResetAccessTracking() {
+	rconfig.accessMu.Lock()
+	defer rconfig.accessMu.Unlock()
+	rconfig.accessed = nil
+}
@@ -0,0 +1,21 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnaccessedKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "USER", "LEGACY"}, nil)
+	rc.Get("HOST")
+	rc.GetInt("PORT")
+
+	if got, want := rc.UnaccessedKeys(), []string{"LEGACY", "USER"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnaccessedKeys() = %v, want %v", got, want)
+	}
+
+	rc.ResetAccessTracking()
+	if got := rc.UnaccessedKeys(); len(got) != 4 {
+		t.Errorf("UnaccessedKeys() after reset = %v, want every key", got)
+	}
+}
@@ -0,0 +1,95 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// IgnoredButSet returns the sorted ignore keys that hold a non-empty
+// value, usually a sign of a stale ignore entry
+func (rconfig *RuntimeConfig) IgnoredButSet() []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	var keys []string
+	for _, key := range sortedKeys(rconfig.ignoreKeys) {
+		if rconfig.ignoreKeys[key] && rconfig.data[key] != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// OrphanIgnoreKeys returns the sorted ignore keys that are not in the
+// data prop, typically typos or stale entries
+func (rconfig *RuntimeConfig) OrphanIgnoreKeys() []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	var keys []string
+	for _, key := range sortedKeys(rconfig.ignoreKeys) {
+		if _, ok := rconfig.data[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// UnregisteredEnv scans the environment for variables starting with
+// prefix and returns the sorted names, with prefix stripped, that are
+// not keys in the data prop
+func (rconfig *RuntimeConfig) UnregisteredEnv(prefix string) []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	var keys []string
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		key, ok := strings.CutPrefix(name, prefix)
+		if !ok || key == "" {
+			continue
+		}
+		if _, registered := rconfig.data[key]; !registered {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// RejectUnknownEnv returns an error naming every env var starting with
+// prefix that is not a registered key, so a typo in a deployment fails
+// at startup, see UnregisteredEnv
+func (rconfig *RuntimeConfig) RejectUnknownEnv(prefix string) error {
+	unknown := rconfig.UnregisteredEnv(prefix)
+	if len(unknown) == 0 {
+		return nil
+	}
+	for i, key := range unknown {
+		unknown[i] = prefix + key
+	}
+	return fmt.Errorf("unknown env vars: %s", strings.Join(unknown, ", "))
+}
+
+// CaseCollisions returns groups of keys that differ only by case, each
+// group and the list of groups sorted
+func (rconfig *RuntimeConfig) CaseCollisions() [][]string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	byFold := make(map[string][]string)
+	for _, key := range sortedKeys(rconfig.data) {
+		folded := strings.ToLower(key)
+		byFold[folded] = append(byFold[folded], key)
+	}
+
+	var groups [][]string
+	for _, folded := range sortedKeys(byFold) {
+		if len(byFold[folded]) > 1 {
+			groups = append(groups, byFold[folded])
+		}
+	}
+	return groups
+}
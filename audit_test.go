@@ -0,0 +1,60 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIgnoredButSet(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"EMPTY_IGNORED", "SET_IGNORED", "NORMAL"}, []string{"EMPTY_IGNORED", "SET_IGNORED"})
+	rc.Set("SET_IGNORED", "x")
+	rc.Set("NORMAL", "y")
+
+	if got, want := rc.IgnoredButSet(), []string{"SET_IGNORED"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IgnoredButSet() = %v, want %v", got, want)
+	}
+}
+
+func TestUnregisteredEnv(t *testing.T) {
+	t.Setenv("RCTEST_HOST", "example.com")
+	t.Setenv("RCTEST_TYPO", "x")
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+
+	if got, want := rc.UnregisteredEnv("RCTEST_"), []string{"TYPO"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnregisteredEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestOrphanIgnoreKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"DEBUG"}, []string{"DEBUG", "DEBGU"})
+	if got, want := rc.OrphanIgnoreKeys(), []string{"DEBGU"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OrphanIgnoreKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestCaseCollisions(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"db_host", "DB_HOST", "Db_Host", "PORT", "USER"}, nil)
+	want := [][]string{{"DB_HOST", "Db_Host", "db_host"}}
+	if got := rc.CaseCollisions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CaseCollisions() = %v, want %v", got, want)
+	}
+
+	if got := NewRuntimeConfig([]string{"HOST", "PORT"}, nil).CaseCollisions(); got != nil {
+		t.Errorf("CaseCollisions() without collisions = %v, want nil", got)
+	}
+}
+
+func TestRejectUnknownEnv(t *testing.T) {
+	t.Setenv("RCREJECT_HOST", "example.com")
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	if err := rc.RejectUnknownEnv("RCREJECT_"); err != nil {
+		t.Errorf("RejectUnknownEnv() error = %v with only known vars, want nil", err)
+	}
+
+	t.Setenv("RCREJECT_HOTS", "typo")
+	err := rc.RejectUnknownEnv("RCREJECT_")
+	if err == nil || !strings.Contains(err.Error(), "RCREJECT_HOTS") {
+		t.Errorf("RejectUnknownEnv() error = %v, want it to name RCREJECT_HOTS", err)
+	}
+}
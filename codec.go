@@ -0,0 +1,67 @@
+package runtimeconfig
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+)
+
+// Codec converts the key value pairs of a RuntimeConfig to and from a
+// serialized format, see JSONCodec
+type Codec interface {
+	Marshal(m map[string]string) ([]byte, error)
+	Unmarshal(b []byte) (map[string]string, error)
+}
+
+// JSONCodec is a Codec encoding the data as a flat JSON object
+type JSONCodec struct{}
+
+// Marshal encodes m as a JSON object
+func (JSONCodec) Marshal(m map[string]string) ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// Unmarshal decodes a JSON object of string values
+func (JSONCodec) Unmarshal(b []byte) (map[string]string, error) {
+	m := make(map[string]string)
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Save writes the data prop to w encoded with c
+func (rconfig *RuntimeConfig) Save(w io.Writer, c Codec) error {
+	rconfig.mu.RLock()
+	data := maps.Clone(rconfig.data)
+	rconfig.mu.RUnlock()
+
+	b, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// Load reads r, decodes it with c and sets every key value pair in the
+// data prop
+func (rconfig *RuntimeConfig) Load(r io.Reader, c Codec) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m, err := c.Unmarshal(b)
+	if err != nil {
+		return err
+	}
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range m {
+		rconfig.write(&changes, rconfig.canonical(key), value)
+	}
+	return nil
+}
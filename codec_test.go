@@ -0,0 +1,30 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "EMPTY"}, nil)
+	rc.Set("HOST", "example.com")
+	rc.Set("PORT", "8080")
+
+	var buf bytes.Buffer
+	if err := rc.Save(&buf, JSONCodec{}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded := NewRuntimeConfig(nil, nil)
+	if err := loaded.Load(&buf, JSONCodec{}); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := loaded.Entries(), rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded Entries() = %v, want %v", got, want)
+	}
+
+	if err := loaded.Load(strings.NewReader("{not json"), JSONCodec{}); err == nil {
+		t.Error("Load() of malformed JSON returned no error")
+	}
+}
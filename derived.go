@@ -0,0 +1,19 @@
+package runtimeconfig
+
+// deriveFunc computes the value of a derived key
+type deriveFunc func(rc *RuntimeConfig) string
+
+// SetDerived makes key a computed key, Get returns fn applied to the
+// RuntimeConfig instead of a stored value, passing nil removes it
+// note: fn runs without the lock held so it may call Get on other keys,
+// derived keys never count as missing
+func (rconfig *RuntimeConfig) SetDerived(key string, fn func(rc *RuntimeConfig) string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if fn == nil {
+		delete(rconfig.derived, key)
+		return
+	}
+	rconfig.derived[key] = fn
+}
@@ -0,0 +1,33 @@
+package runtimeconfig
+
+import "testing"
+
+func TestSetDerived(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "ADDR"}, nil)
+	rc.SetDerived("ADDR", func(rc *RuntimeConfig) string {
+		return rc.Get("HOST") + ":" + rc.Get("PORT")
+	})
+	rc.Set("HOST", "localhost")
+	rc.Set("PORT", "80")
+	if got := rc.Get("ADDR"); got != "localhost:80" {
+		t.Errorf("Get(ADDR) = %q, want %q", got, "localhost:80")
+	}
+
+	rc.Set("HOST", "example.com")
+	rc.Set("PORT", "8080")
+	if got := rc.Get("ADDR"); got != "example.com:8080" {
+		t.Errorf("Get(ADDR) = %q after updating both base keys, want %q", got, "example.com:8080")
+	}
+	if missing := rc.MissingKeys(); len(missing) != 0 {
+		t.Errorf("MissingKeys() = %v, want derived keys exempt", missing)
+	}
+	rc.SetRequired("ADDR")
+	if err := rc.ValidateAll(); err != nil {
+		t.Errorf("ValidateAll() error = %v, want a required derived key exempt", err)
+	}
+
+	rc.SetDerived("ADDR", nil)
+	if got := rc.Get("ADDR"); got != "" {
+		t.Errorf("Get(ADDR) = %q after removing the derivation, want the stored empty value", got)
+	}
+}
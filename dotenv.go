@@ -0,0 +1,167 @@
+package runtimeconfig
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// mWatchInterval package const for how often WatchFile checks the file
+const mWatchInterval time.Duration = 500 * time.Millisecond
+
+// parseDotEnv reads KEY=VALUE lines from r, blank lines and # comments
+// are skipped, an export prefix is allowed and quoted values are
+// unquoted as GetUnquoted does
+func parseDotEnv(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		values[key] = unquote(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// readDotEnv parses the .env file at path
+func readDotEnv(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values, err := parseDotEnv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// LoadDotEnvFiles loads the .env files at paths in order, later files
+// overriding earlier ones, e.g. .env then .env.local then .env.prod,
+// files that do not exist are skipped
+// note: every file is parsed before anything is stored, so a malformed
+// file leaves the values untouched and its error is returned
+func (rconfig *RuntimeConfig) LoadDotEnvFiles(paths ...string) error {
+	merged := make(map[string]string)
+	var errs []error
+	for _, path := range paths {
+		values, err := readDotEnv(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	rconfig.applyValues(merged)
+	return nil
+}
+
+// EqualsDotEnv compares the data prop against the .env file at path,
+// e.g. a golden file committed for tests, and returns whether they match
+// along with the sorted keys that differ or are present on one side only
+func (rconfig *RuntimeConfig) EqualsDotEnv(path string) (bool, []string, error) {
+	golden, err := readDotEnv(path)
+	if err != nil {
+		return false, nil, err
+	}
+
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	var diff []string
+	for key, value := range rconfig.data {
+		if expected, ok := golden[key]; !ok || expected != value {
+			diff = append(diff, key)
+		}
+	}
+	for key := range golden {
+		if _, ok := rconfig.data[key]; !ok {
+			diff = append(diff, key)
+		}
+	}
+	slices.Sort(diff)
+	return len(diff) == 0, diff, nil
+}
+
+// applyValues stores values and returns the sorted keys whose value
+// changed
+func (rconfig *RuntimeConfig) applyValues(values map[string]string) []string {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range values {
+		rconfig.write(&changes, rconfig.canonical(key), value)
+	}
+
+	changed := make([]string, 0, len(changes))
+	for _, c := range changes {
+		changed = append(changed, c.key)
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// WatchFile polls the modification time of the .env file at path and
+// reloads it whenever it changes, onChange receives the sorted keys
+// whose value changed, it returns nil once ctx is done
+// note: a file that is briefly missing or malformed, as during an atomic
+// replace, is retried on the next poll, only a file missing at the start
+// is an error
+func (rconfig *RuntimeConfig) WatchFile(ctx context.Context, path string, onChange func(changed []string)) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	modTime, size := info.ModTime(), info.Size()
+
+	ticker := time.NewTicker(mWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+			continue
+		}
+		values, err := readDotEnv(path)
+		if err != nil {
+			continue
+		}
+		modTime, size = info.ModTime(), info.Size()
+		if changed := rconfig.applyValues(values); len(changed) > 0 && onChange != nil {
+			onChange(changed)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package runtimeconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes contents to name in dir and returns its path
+func writeFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEqualsDotEnv(t *testing.T) {
+	golden := writeFile(t, t.TempDir(), "golden.env", "# expected\nHOST=example.com\nPORT=\"8080\"\n")
+
+	rc := NewRuntimeConfig(nil, nil)
+	rc.Set("HOST", "example.com")
+	rc.Set("PORT", "8080")
+	if equal, diff, err := rc.EqualsDotEnv(golden); err != nil || !equal || len(diff) != 0 {
+		t.Errorf("EqualsDotEnv() = %v, %v, %v, want true, [], nil", equal, diff, err)
+	}
+
+	rc.Set("PORT", "9090")
+	rc.Set("EXTRA", "x")
+	rc.Delete("HOST")
+	equal, diff, err := rc.EqualsDotEnv(golden)
+	if err != nil || equal || !reflect.DeepEqual(diff, []string{"EXTRA", "HOST", "PORT"}) {
+		t.Errorf("EqualsDotEnv() = %v, %v, %v, want false, [EXTRA HOST PORT], nil", equal, diff, err)
+	}
+
+	if _, _, err := rc.EqualsDotEnv(filepath.Join(t.TempDir(), "missing.env")); err == nil {
+		t.Error("EqualsDotEnv() of a missing file returned no error")
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path := writeFile(t, t.TempDir(), ".env", "HOST=a\nPORT=80\n")
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	rc.Set("HOST", "a")
+	rc.Set("PORT", "80")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan []string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- rc.WatchFile(ctx, path, func(keys []string) { changed <- keys })
+	}()
+
+	// the watcher may not have taken its first stat yet, so keep
+	// rewriting with a new value and a later mod time until it reacts
+	var keys []string
+	deadline := time.After(10 * mWatchInterval)
+	for i := 1; keys == nil; i++ {
+		writeFile(t, filepath.Dir(path), ".env", fmt.Sprintf("HOST=b%d\nPORT=80\n", i))
+		later := time.Now().Add(time.Duration(i) * time.Second)
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case keys = <-changed:
+		case <-time.After(2 * mWatchInterval):
+		case <-deadline:
+			t.Fatal("onChange was not called after the file was rewritten")
+		}
+	}
+	if want := []string{"HOST"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("onChange keys = %v, want %v", keys, want)
+	}
+	if got := rc.Get("HOST"); !strings.HasPrefix(got, "b") {
+		t.Errorf("Get(HOST) = %q, want the rewritten value", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchFile() error = %v after cancel, want nil", err)
+	}
+
+	if err := rc.WatchFile(context.Background(), filepath.Join(t.TempDir(), "missing"), nil); err == nil {
+		t.Error("WatchFile() on a missing file returned no error")
+	}
+}
+
+func TestLoadDotEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, ".env", "# base\nHOST=localhost\nPORT=8080\nexport NAME=\"api\"\n")
+	local := writeFile(t, dir, ".env.local", "PORT=9090\n")
+	missing := filepath.Join(dir, ".env.prod")
+
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "NAME"}, nil)
+	if err := rc.LoadDotEnvFiles(base, missing, local); err != nil {
+		t.Fatalf("LoadDotEnvFiles() error = %v", err)
+	}
+	want := []string{"HOST=localhost", "NAME=api", "PORT=9090"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+
+	broken := writeFile(t, dir, ".env.broken", "HOST=other\nnot a pair\n")
+	if err := rc.LoadDotEnvFiles(broken); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("LoadDotEnvFiles() error = %v, want a line 2 error", err)
+	}
+	if got := rc.Get("HOST"); got != "localhost" {
+		t.Errorf("Get(HOST) = %q after a malformed file, want it untouched", got)
+	}
+}
@@ -0,0 +1,386 @@
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SetEnvFunc replaces the function used to look up env values, passing
+// nil restores os.LookupEnv
+// note: useful for tests and for loading from alternate sources
+func (rconfig *RuntimeConfig) SetEnvFunc(fn func(key string) (string, bool)) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if fn == nil {
+		fn = os.LookupEnv
+	}
+	rconfig.lookupEnv = fn
+}
+
+// DisableEnvLoading locks in the current values, every env loading
+// method becomes a no-op and those returning an error report
+// ErrEnvLoadingDisabled
+func (rconfig *RuntimeConfig) DisableEnvLoading() {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.envDisabled = true
+}
+
+// EnableLazyEnv defers env loading to Get, the first read of a
+// registered key with an empty value loads it as LoadValueFromEnv would
+// and the result is kept, so each key is looked up at most once
+// note: does nothing after DisableEnvLoading
+func (rconfig *RuntimeConfig) EnableLazyEnv() {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.lazyEnv = true
+}
+
+// loadLazy loads the env value of a registered key for Get and returns
+// the resulting value
+func (rconfig *RuntimeConfig) loadLazy(key string) string {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+
+	current, registered := rconfig.data[key]
+	if !registered || rconfig.lazyDone[key] || rconfig.envDisabled {
+		return rconfig.live(key)
+	}
+	rconfig.lazyDone[key] = true
+	if current != "" {
+		return rconfig.live(key) // set by another goroutine while we waited
+	}
+	value, source := rconfig.envSource(key)
+	rconfig.writeFrom(&changes, key, value, source)
+	return value
+}
+
+// envLoadingDisabled reports whether DisableEnvLoading was called
+func (rconfig *RuntimeConfig) envLoadingDisabled() bool {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return rconfig.envDisabled
+}
+
+// SetMissHandler sets a fallback consulted by Get when key is absent or
+// empty, e.g. a secret manager lookup, values it supplies are cached
+// unless SetMissCaching is off while a declined key is asked again on
+// the next Get
+// note: passing nil removes the handler, the cache is cleared either
+// way and the handler runs without the lock held, writing or deleting a
+// key drops its cached value
+func (rconfig *RuntimeConfig) SetMissHandler(fn func(key string) (string, bool)) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.missHandler = fn
+	rconfig.missCache = make(map[string]missEntry)
+}
+
+// SetMissCaching toggles caching the values supplied by the
+// SetMissHandler handler, on by default, turning it off clears the cache
+// so the handler is asked on every Get of an empty key
+func (rconfig *RuntimeConfig) SetMissCaching(on bool) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.noMissCache = !on
+	if !on {
+		rconfig.missCache = make(map[string]missEntry)
+	}
+}
+
+// missEntry is a value supplied by the miss handler, cached until expiry
+// when the key was stored with SetWithTTL
+type missEntry struct {
+	value  string
+	expiry time.Time
+}
+
+// cachedMiss returns the cached miss handler value of key unless it has
+// expired, callers must hold the lock
+func (rconfig *RuntimeConfig) cachedMiss(key string) (string, bool) {
+	entry, ok := rconfig.missCache[key]
+	if !ok || (!entry.expiry.IsZero() && !rconfig.now().Before(entry.expiry)) {
+		return mKeyDefaultValue, false
+	}
+	return entry.value, true
+}
+
+// handleMiss asks handler for the value of key and caches a supplied one,
+// for the TTL of the key if it has one
+func (rconfig *RuntimeConfig) handleMiss(key string, handler func(key string) (string, bool)) string {
+	value, ok := handler(key)
+	if !ok {
+		return mKeyDefaultValue
+	}
+
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if !rconfig.noMissCache {
+		entry := missEntry{value: value}
+		if ttl, ok := rconfig.ttls[key]; ok {
+			entry.expiry = rconfig.now().Add(ttl)
+		}
+		rconfig.missCache[key] = entry
+	}
+	return value
+}
+
+// SetAliases registers alternate env var names for key, consulted in
+// order when the key itself is unset in the environment
+func (rconfig *RuntimeConfig) SetAliases(key string, aliases ...string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.aliases[rconfig.canonical(key)] = append([]string(nil), aliases...)
+}
+
+// AliasConflicts returns, per key, the alias env var names that are set
+// to differing values, keys whose set aliases agree are omitted
+// note: an alias set to empty counts as unset, as in LoadValueFromEnv
+func (rconfig *RuntimeConfig) AliasConflicts() map[string][]string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	conflicts := make(map[string][]string)
+	for key, aliases := range rconfig.aliases {
+		var set []string
+		values := make(map[string]bool)
+		for _, alias := range aliases {
+			if value, _ := rconfig.lookupEnv(alias); value != "" {
+				set = append(set, alias)
+				values[value] = true
+			}
+		}
+		if len(values) > 1 {
+			conflicts[key] = set
+		}
+	}
+	return conflicts
+}
+
+// ValidateAliases returns an error for every alias that is also a
+// registered key and for every alias shared by more than one key, both
+// of which make the env lookup ambiguous
+func (rconfig *RuntimeConfig) ValidateAliases() error {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	var errs []error
+	owners := make(map[string][]string)
+	for _, key := range sortedKeys(rconfig.aliases) {
+		for _, alias := range rconfig.aliases[key] {
+			if _, ok := rconfig.data[alias]; ok {
+				errs = append(errs, fmt.Errorf("alias '%s' of key '%s' is a registered key", alias, key))
+			}
+			if !slices.Contains(owners[alias], key) {
+				owners[alias] = append(owners[alias], key)
+			}
+		}
+	}
+	for _, alias := range sortedKeys(owners) {
+		if len(owners[alias]) > 1 {
+			errs = append(errs, fmt.Errorf("alias '%s' is shared by keys '%s'", alias, strings.Join(owners[alias], "', '")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PreviewEnvLoad reports what LoadValueFromEnv would change without
+// writing anything, each entry holds the {current, wouldBe} pair
+// note: keys that would not change are omitted, the preview is empty
+// after DisableEnvLoading
+func (rconfig *RuntimeConfig) PreviewEnvLoad() map[string][2]string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	preview := make(map[string][2]string)
+	if rconfig.envDisabled {
+		return preview
+	}
+	for key, current := range rconfig.data {
+		if next := rconfig.envValue(key); next != current {
+			preview[key] = [2]string{current, next}
+		}
+	}
+	return preview
+}
+
+// DiffEnv returns, per key whose stored value no longer matches the
+// environment, the {storedValue, envValue} pair, which shows env drift
+// since the last load
+// note: envValue honours aliases and defaults as LoadValueFromEnv does,
+// so this matches PreviewEnvLoad and is likewise empty after
+// DisableEnvLoading
+func (rconfig *RuntimeConfig) DiffEnv() map[string][2]string {
+	return rconfig.PreviewEnvLoad()
+}
+
+// LoadFileBackedEnv reads secrets mounted as files, for each key whose
+// KEY+suffix env var is set (e.g. DB_PASSWORD_FILE) the trimmed file
+// contents are stored, taking precedence over the plain KEY var
+// note: files that can not be read are reported and skipped, does
+// nothing after DisableEnvLoading
+func (rconfig *RuntimeConfig) LoadFileBackedEnv(suffix string) {
+	files := make(map[string]string)
+	rconfig.mu.RLock()
+	if rconfig.envDisabled {
+		rconfig.mu.RUnlock()
+		return
+	}
+	for key := range rconfig.data {
+		if path, _ := rconfig.lookupEnv(key + suffix); path != "" {
+			files[key] = path
+		}
+	}
+	rconfig.mu.RUnlock()
+
+	values := make(map[string]string, len(files))
+	for key, path := range files {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Key '%s' could not read '%s': %v\n", key, path, err)
+			continue
+		}
+		values[key] = strings.TrimSpace(string(contents))
+	}
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range values {
+		rconfig.writeFrom(&changes, key, value, SourceEnv)
+	}
+}
+
+// ReloadKeys reloads only the listed keys from env as LoadValueFromEnv
+// would and returns those whose value changed, in the order given
+// note: keys not in the data prop are skipped, does nothing after
+// DisableEnvLoading
+func (rconfig *RuntimeConfig) ReloadKeys(keys ...string) []string {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if rconfig.envDisabled {
+		return nil
+	}
+
+	var changed []string
+	for _, key := range keys {
+		key = rconfig.canonical(key)
+		if _, ok := rconfig.data[key]; !ok {
+			continue
+		}
+		before := len(changes)
+		value, source := rconfig.envSource(key)
+		rconfig.writeFrom(&changes, key, value, source)
+		if len(changes) > before {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// LoadEnvWithSnapshot behaves like LoadValueFromEnv and returns the
+// sorted keys it changed along with a restore func that puts their prior
+// values back, the load and snapshot happen under a single lock
+// note: after DisableEnvLoading nothing is loaded and restore is a no-op
+func (rconfig *RuntimeConfig) LoadEnvWithSnapshot() (restore func(), changed []string) {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+
+	saved := make(map[string]prior)
+	if !rconfig.envDisabled {
+		for _, key := range sortedKeys(rconfig.data) {
+			value, source := rconfig.envSource(key)
+			if value != rconfig.data[key] {
+				rconfig.remember(saved, key)
+			}
+			rconfig.writeFrom(&changes, key, value, source)
+		}
+	}
+	changed = sortedKeys(saved)
+
+	restore = func() {
+		var changes []change
+		defer rconfig.notify(&changes)
+		rconfig.mu.Lock()
+		defer rconfig.mu.Unlock()
+		rconfig.unshare()
+		for _, key := range sortedKeys(saved) {
+			if current := rconfig.data[key]; current != saved[key].value {
+				changes = append(changes, change{key: key, old: current, new: saved[key].value})
+			}
+		}
+		rconfig.rollback(saved)
+	}
+	return restore, changed
+}
+
+// LoadValueFromEnvContext behaves like LoadValueFromEnv but checks ctx
+// while loading and returns its error if it is done, in which case no
+// values are written
+func (rconfig *RuntimeConfig) LoadValueFromEnvContext(ctx context.Context) error {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if rconfig.envDisabled {
+		return ErrEnvLoadingDisabled
+	}
+
+	values := make(map[string]string, len(rconfig.data))
+	sources := make(map[string]Source, len(rconfig.data))
+	for key := range rconfig.data {
+		if len(values)%mCtxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		values[key], sources[key] = rconfig.envSource(key)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		rconfig.writeFrom(&changes, key, value, sources[key])
+	}
+	return nil
+}
+
+// WaitUntilLoaded reloads from env every poll interval until
+// ValuesLoaded is true, returning the context error if ctx is done first
+// note: returns ErrEnvLoadingDisabled if values are still missing after
+// DisableEnvLoading since they can no longer arrive
+func (rconfig *RuntimeConfig) WaitUntilLoaded(ctx context.Context, poll time.Duration) error {
+	if poll <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		rconfig.LoadValueFromEnv()
+		if rconfig.ValuesLoaded() {
+			return nil
+		}
+		if rconfig.envLoadingDisabled() {
+			return ErrEnvLoadingDisabled
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
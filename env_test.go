@@ -0,0 +1,354 @@
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPreviewEnvLoad(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "SAME"}, nil)
+	rc.Set("HOST", "old")
+	rc.Set("SAME", "kept")
+	rc.SetEnvFunc(envFunc(map[string]string{"HOST": "new", "PORT": "8080", "SAME": "kept"}))
+
+	want := map[string][2]string{
+		"HOST": {"old", "new"},
+		"PORT": {"", "8080"},
+	}
+	if got := rc.PreviewEnvLoad(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PreviewEnvLoad() = %v, want %v", got, want)
+	}
+	if got := rc.Get("HOST"); got != "old" {
+		t.Errorf("Get(HOST) = %q after preview, want it unchanged", got)
+	}
+	if got := rc.Get("PORT"); got != "" {
+		t.Errorf("Get(PORT) = %q after preview, want it unchanged", got)
+	}
+}
+
+func TestLoadFileBackedEnv(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secret, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rc := NewRuntimeConfig([]string{"DB_PASSWORD", "DB_USER"}, nil)
+	rc.SetEnvFunc(envFunc(map[string]string{
+		"DB_PASSWORD":      "from-env",
+		"DB_PASSWORD_FILE": secret,
+		"DB_USER":          "admin",
+	}))
+	rc.LoadValueFromEnv()
+	rc.LoadFileBackedEnv("_FILE")
+
+	if got := rc.Get("DB_PASSWORD"); got != "from-file" {
+		t.Errorf("Get(DB_PASSWORD) = %q, want the trimmed file contents %q", got, "from-file")
+	}
+	if got := rc.Get("DB_USER"); got != "admin" {
+		t.Errorf("Get(DB_USER) = %q, want the plain env value %q", got, "admin")
+	}
+}
+
+func TestLoadValueFromEnvContextCancelled(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	rc.SetEnvFunc(envFunc(map[string]string{"HOST": "example.com"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rc.LoadValueFromEnvContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadValueFromEnvContext() error = %v, want context.Canceled", err)
+	}
+	if got := rc.Get("HOST"); got != "" {
+		t.Errorf("Get(HOST) = %q, want nothing written", got)
+	}
+
+	if err := rc.LoadValueFromEnvContext(context.Background()); err != nil {
+		t.Fatalf("LoadValueFromEnvContext() error = %v", err)
+	}
+	if got := rc.Get("HOST"); got != "example.com" {
+		t.Errorf("Get(HOST) = %q, want %q", got, "example.com")
+	}
+}
+
+func TestSetEnvFunc(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	var looked []string
+	rc.SetEnvFunc(func(key string) (string, bool) {
+		looked = append(looked, key)
+		if key == "HOST" {
+			return "fake-host", true
+		}
+		return "", false
+	})
+	rc.LoadValueFromEnv()
+	if got := rc.Get("HOST"); got != "fake-host" {
+		t.Errorf("Get(HOST) = %q, want the fake env value %q", got, "fake-host")
+	}
+	if len(looked) != 2 {
+		t.Errorf("fake env looked up %v, want both keys", looked)
+	}
+
+	t.Setenv("HOST", "real-host")
+	rc.SetEnvFunc(nil)
+	rc.LoadValueFromEnv()
+	if got := rc.Get("HOST"); got != "real-host" {
+		t.Errorf("Get(HOST) = %q after SetEnvFunc(nil), want the os env value %q", got, "real-host")
+	}
+}
+
+func TestWaitUntilLoaded(t *testing.T) {
+	var mu sync.Mutex
+	vars := map[string]string{}
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	rc.SetEnvFunc(func(key string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		value, ok := vars[key]
+		return value, ok
+	})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		vars["HOST"] = "example.com"
+		mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := rc.WaitUntilLoaded(ctx, 5*time.Millisecond); err != nil {
+		t.Fatalf("WaitUntilLoaded() error = %v", err)
+	}
+	if got := rc.Get("HOST"); got != "example.com" {
+		t.Errorf("Get(HOST) = %q, want %q", got, "example.com")
+	}
+}
+
+func TestAliasConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want map[string][]string
+	}{
+		{"agree", map[string]string{"DATABASE_URL": "pg://a", "DB_URL": "pg://a"}, map[string][]string{}},
+		{"disagree", map[string]string{"DATABASE_URL": "pg://a", "DB_URL": "pg://b"}, map[string][]string{"DSN": {"DATABASE_URL", "DB_URL"}}},
+		{"empty alias", map[string]string{"DATABASE_URL": "pg://a", "DB_URL": ""}, map[string][]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"DSN"}, nil)
+			rc.SetAliases("DSN", "DATABASE_URL", "DB_URL")
+			rc.SetEnvFunc(envFunc(tt.env))
+			if got := rc.AliasConflicts(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AliasConflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffEnv(t *testing.T) {
+	env := map[string]string{"HOST": "a", "PORT": "80"}
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	rc.SetEnvFunc(envFunc(env))
+	rc.LoadValueFromEnv()
+	if got := rc.DiffEnv(); len(got) != 0 {
+		t.Errorf("DiffEnv() right after loading = %v, want empty", got)
+	}
+
+	env["HOST"] = "b"
+	want := map[string][2]string{"HOST": {"a", "b"}}
+	if got := rc.DiffEnv(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestDisableEnvLoading(t *testing.T) {
+	env := map[string]string{"HOST": "first"}
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	rc.SetEnvFunc(envFunc(env))
+	rc.LoadValueFromEnv()
+	rc.DisableEnvLoading()
+
+	env["HOST"] = "second"
+	rc.LoadValueFromEnv()
+	if got := rc.ReloadKeys("HOST"); got != nil {
+		t.Errorf("ReloadKeys() = %v after disabling, want nil", got)
+	}
+	if err := rc.LoadValueFromEnvContext(context.Background()); !errors.Is(err, ErrEnvLoadingDisabled) {
+		t.Errorf("LoadValueFromEnvContext() error = %v, want ErrEnvLoadingDisabled", err)
+	}
+	if got := rc.Get("HOST"); got != "first" {
+		t.Errorf("Get(HOST) = %q, want the value locked in before disabling", got)
+	}
+	rc.Set("HOST", "manual")
+	if got := rc.Get("HOST"); got != "manual" {
+		t.Errorf("Get(HOST) = %q, want Set to keep working", got)
+	}
+	if got := rc.PreviewEnvLoad(); len(got) != 0 {
+		t.Errorf("PreviewEnvLoad() = %v after disabling, want empty", got)
+	}
+	if got := rc.DiffEnv(); len(got) != 0 {
+		t.Errorf("DiffEnv() = %v after disabling, want empty", got)
+	}
+}
+
+func TestSetMissHandler(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"SECRET", "OTHER"}, nil)
+	calls := map[string]int{}
+	rc.SetMissHandler(func(key string) (string, bool) {
+		calls[key]++
+		if key == "SECRET" {
+			return "from-vault", true
+		}
+		return "", false
+	})
+
+	for i := 0; i < 2; i++ {
+		if got := rc.Get("SECRET"); got != "from-vault" {
+			t.Errorf("Get(SECRET) = %q, want the supplied %q", got, "from-vault")
+		}
+		if got := rc.Get("OTHER"); got != "" {
+			t.Errorf("Get(OTHER) = %q, want empty when declined", got)
+		}
+	}
+	if calls["SECRET"] != 1 {
+		t.Errorf("handler asked for SECRET %d times, want 1 as the value is cached", calls["SECRET"])
+	}
+	if calls["OTHER"] != 2 {
+		t.Errorf("handler asked for OTHER %d times, want 2 as declines are not cached", calls["OTHER"])
+	}
+
+	rc.Set("SECRET", "local")
+	if got := rc.Get("SECRET"); got != "local" {
+		t.Errorf("Get(SECRET) = %q, want a stored value to win over the handler", got)
+	}
+
+	rc.Set("SECRET", "")
+	if got := rc.Get("SECRET"); got != "from-vault" || calls["SECRET"] != 2 {
+		t.Errorf("Get(SECRET) = %q after clearing with %d handler calls, want the handler asked again", got, calls["SECRET"])
+	}
+	rc.Delete("SECRET")
+	rc.Get("SECRET")
+	if calls["SECRET"] != 3 {
+		t.Errorf("handler asked for SECRET %d times after Delete, want 3", calls["SECRET"])
+	}
+}
+
+func TestSetMissCaching(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"SECRET"}, nil)
+	calls := 0
+	rc.SetMissHandler(func(key string) (string, bool) {
+		calls++
+		return "v" + strconv.Itoa(calls), true
+	})
+	rc.SetMissCaching(false)
+
+	if a, b := rc.Get("SECRET"), rc.Get("SECRET"); a != "v1" || b != "v2" {
+		t.Errorf("Get(SECRET) = %q then %q, want the handler asked each time", a, b)
+	}
+
+	rc.SetMissCaching(true)
+	if a, b := rc.Get("SECRET"), rc.Get("SECRET"); a != "v3" || b != "v3" {
+		t.Errorf("Get(SECRET) = %q then %q, want the value cached again", a, b)
+	}
+}
+
+func TestEnableLazyEnv(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	lookups := map[string]int{}
+	rc.SetEnvFunc(func(key string) (string, bool) {
+		lookups[key]++
+		if key == "HOST" {
+			return "example.com", true
+		}
+		return "", false
+	})
+	rc.EnableLazyEnv()
+
+	if len(lookups) != 0 {
+		t.Fatalf("env looked up %v before any Get, want nothing", lookups)
+	}
+	for i := 0; i < 3; i++ {
+		if got := rc.Get("HOST"); got != "example.com" {
+			t.Errorf("Get(HOST) = %q, want %q", got, "example.com")
+		}
+		rc.Get("PORT")
+	}
+	if lookups["HOST"] != 1 || lookups["PORT"] != 1 {
+		t.Errorf("env lookups = %v, want each key looked up once", lookups)
+	}
+	rc.Get("UNREGISTERED")
+	if lookups["UNREGISTERED"] != 0 {
+		t.Error("env looked up an unregistered key")
+	}
+}
+
+func TestValidateAliases(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"DSN", "HOST"}, nil)
+	rc.SetAliases("DSN", "DATABASE_URL")
+	if err := rc.ValidateAliases(); err != nil {
+		t.Errorf("ValidateAliases() error = %v, want nil", err)
+	}
+
+	rc.SetAliases("HOST", "DSN")
+	if err := rc.ValidateAliases(); err == nil || !strings.Contains(err.Error(), "alias 'DSN' of key 'HOST' is a registered key") {
+		t.Errorf("ValidateAliases() error = %v, want the registered key collision", err)
+	}
+
+	rc.SetAliases("HOST", "DATABASE_URL")
+	if err := rc.ValidateAliases(); err == nil || !strings.Contains(err.Error(), "alias 'DATABASE_URL' is shared by keys 'DSN', 'HOST'") {
+		t.Errorf("ValidateAliases() error = %v, want the shared alias collision", err)
+	}
+}
+
+func TestLoadEnvWithSnapshot(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "NAME"}, nil)
+	rc.Set("HOST", "localhost")
+	rc.Set("PORT", "8080")
+	rc.Set("NAME", "api")
+	rc.SetEnvFunc(envFunc(map[string]string{"HOST": "example.com", "PORT": "8080", "NAME": "web"}))
+
+	restore, changed := rc.LoadEnvWithSnapshot()
+	if want := []string{"HOST", "NAME"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("LoadEnvWithSnapshot() changed = %v, want %v", changed, want)
+	}
+	if got := rc.Get("HOST"); got != "example.com" {
+		t.Errorf("Get(HOST) = %q after the load, want %q", got, "example.com")
+	}
+
+	restore()
+	for key, want := range map[string]string{"HOST": "localhost", "PORT": "8080", "NAME": "api"} {
+		if got := rc.Get(key); got != want {
+			t.Errorf("Get(%s) = %q after restore, want %q", key, got, want)
+		}
+	}
+}
+
+func TestReloadKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "NAME"}, nil)
+	rc.Set("HOST", "localhost")
+	rc.Set("PORT", "8080")
+	rc.Set("NAME", "api")
+	rc.SetEnvFunc(envFunc(map[string]string{"HOST": "example.com", "PORT": "8080", "NAME": "web"}))
+
+	changed := rc.ReloadKeys("HOST", "PORT", "MISSING")
+	if want := []string{"HOST"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("ReloadKeys() = %v, want %v", changed, want)
+	}
+	for key, want := range map[string]string{"HOST": "example.com", "PORT": "8080", "NAME": "api"} {
+		if got := rc.Get(key); got != want {
+			t.Errorf("Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+	if rc.Has("MISSING") {
+		t.Error("ReloadKeys() added a key that was not in the data prop")
+	}
+}
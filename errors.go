@@ -0,0 +1,69 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEnvLoadingDisabled is returned by env loading methods after
+// DisableEnvLoading has been called
+var ErrEnvLoadingDisabled = errors.New("env loading is disabled")
+
+// ErrMaxKeysReached is returned when adding a key would exceed the
+// limit set with SetMaxKeys
+var ErrMaxKeysReached = errors.New("max keys reached")
+
+// ParseError is returned by the typed getters when a value can not be
+// converted to the requested kind
+type ParseError struct {
+	Key   string // key that was read
+	Value string // raw value held by the key, masked for sensitive keys
+	Kind  string // kind that was requested: int, bool, duration, ...
+	Err   error  // underlying conversion error
+}
+
+// Error formats the key, kind and value that failed to parse
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("key '%s' has invalid %s value '%s': %v", e.Key, e.Kind, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying conversion error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// redactedError replaces the text of err while keeping it in the chain
+// for errors.Is and errors.As
+type redactedError struct {
+	msg string
+	err error
+}
+
+// Error returns the redacted text
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// parseError is newParseError taking the read lock
+func (rconfig *RuntimeConfig) parseError(key, value, kind string, err error) *ParseError {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return rconfig.newParseError(key, value, kind, err)
+}
+
+// newParseError returns a *ParseError for value of key, when the key is
+// sensitive the value is masked in Value and in the text of err, callers
+// must hold the lock
+func (rconfig *RuntimeConfig) newParseError(key, value, kind string, err error) *ParseError {
+	if value != "" && rconfig.isSensitive(rconfig.canonical(key)) {
+		masked := strings.ReplaceAll(err.Error(), value, mSensitiveMask)
+		return &ParseError{Key: key, Value: mSensitiveMask, Kind: kind, Err: &redactedError{msg: masked, err: err}}
+	}
+	return &ParseError{Key: key, Value: value, Kind: kind, Err: err}
+}
@@ -0,0 +1,54 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT"}, nil)
+	rc.Set("PORT", "eighty")
+
+	_, err := rc.GetInt("PORT")
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("GetInt() error = %v, want a *ParseError", err)
+	}
+	if pe.Key != "PORT" || pe.Value != "eighty" || pe.Kind != "int" {
+		t.Errorf("ParseError = %+v, want Key PORT, Value eighty, Kind int", pe)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("GetInt() error = %v, want it to wrap strconv.ErrSyntax", err)
+	}
+	if want := `key 'PORT' has invalid int value 'eighty': strconv.Atoi: parsing "eighty": invalid syntax`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestParseErrorMasksSensitiveValues(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"API_KEY", "RETRIES"}, nil)
+	rc.MarkSensitive("API_KEY", "RETRIES")
+	rc.Set("API_KEY", "sk-live-secret")
+	rc.Set("RETRIES", "sk-live-secret")
+	rc.SetType("API_KEY", TypeInt)
+
+	_, getErr := rc.GetInt("API_KEY")
+	_, incErr := rc.Increment("RETRIES", 1)
+	for _, err := range []error{getErr, rc.ValidateAll(), incErr} {
+		if err == nil {
+			t.Fatal("error = nil, want a parse failure")
+		}
+		if strings.Contains(err.Error(), "sk-live-secret") {
+			t.Errorf("Error() = %q, leaks the sensitive value", err)
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Value != mSensitiveMask {
+			t.Errorf("error = %v, want a *ParseError with a masked Value", err)
+		}
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("error = %v, want it to still wrap strconv.ErrSyntax", err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package runtimeconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Entries returns every key value pair as KEY=VALUE sorted by key,
+// including empty values
+// note: non-empty sensitive values are masked
+func (rconfig *RuntimeConfig) Entries() []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	entries := make([]string, 0, len(rconfig.data))
+	for _, key := range sortedKeys(rconfig.data) {
+		entries = append(entries, key+"="+rconfig.displayValue(key))
+	}
+	return entries
+}
+
+// SafeFingerprint returns a hex sha256 of the sorted keys and their
+// values, safe to log as a change detector since sensitive values only
+// contribute whether they are set
+// note: rotating a secret leaves the fingerprint unchanged
+func (rconfig *RuntimeConfig) SafeFingerprint() string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	h := sha256.New()
+	for _, key := range sortedKeys(rconfig.data) {
+		io.WriteString(h, key+"\x00"+rconfig.displayValue(key)+"\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// shellName matches the keys that are valid shell variable names
+var shellName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WriteShellExports writes an export KEY='value' line to w for every
+// non-empty key in sorted order, embedded single quotes are escaped
+// note: sensitive values are written as is, see WriteShellExportsMasked,
+// keys that are not valid shell names, such as db.host, are skipped
+func (rconfig *RuntimeConfig) WriteShellExports(w io.Writer) {
+	rconfig.writeShellExports(w, false)
+}
+
+// WriteShellExportsMasked behaves like WriteShellExports but masks
+// sensitive values
+func (rconfig *RuntimeConfig) WriteShellExportsMasked(w io.Writer) {
+	rconfig.writeShellExports(w, true)
+}
+
+// writeShellExports writes the export lines, optionally masking
+// sensitive values
+func (rconfig *RuntimeConfig) writeShellExports(w io.Writer, mask bool) {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	for _, key := range sortedKeys(rconfig.data) {
+		value := rconfig.data[key]
+		if value == "" || !shellName.MatchString(key) {
+			continue
+		}
+		if mask {
+			value = rconfig.displayValue(key)
+		}
+		fmt.Fprintf(w, "export %s='%s'\n", key, strings.ReplaceAll(value, "'", `'\''`))
+	}
+}
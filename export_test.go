@@ -0,0 +1,82 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEntries(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "API_TOKEN", "HOST", "EMPTY"}, nil)
+	rc.MarkSensitive("API_TOKEN")
+	rc.Set("PORT", "8080")
+	rc.Set("API_TOKEN", "abc123")
+	rc.Set("HOST", "example.com")
+
+	want := []string{"API_TOKEN=********", "EMPTY=", "HOST=example.com", "PORT=8080"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+}
+
+func TestSafeFingerprint(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "API_TOKEN"}, nil)
+	rc.MarkSensitive("API_TOKEN")
+	rc.Set("HOST", "a")
+	rc.Set("API_TOKEN", "secret-1")
+	base := rc.SafeFingerprint()
+	if len(base) != 64 {
+		t.Errorf("SafeFingerprint() = %q, want a hex sha256", base)
+	}
+
+	rc.Set("API_TOKEN", "secret-2")
+	if got := rc.SafeFingerprint(); got != base {
+		t.Error("SafeFingerprint() changed when only a secret rotated")
+	}
+
+	rc.Set("HOST", "b")
+	if got := rc.SafeFingerprint(); got == base {
+		t.Error("SafeFingerprint() unchanged after a non-secret value changed")
+	}
+
+	rc.Set("HOST", "a")
+	rc.Set("API_TOKEN", "")
+	if got := rc.SafeFingerprint(); got == base {
+		t.Error("SafeFingerprint() unchanged after a secret was unset")
+	}
+}
+
+func TestWriteShellExports(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"NAME", "API_TOKEN", "EMPTY"}, nil)
+	rc.MarkSensitive("API_TOKEN")
+	rc.Set("NAME", "it's here")
+	rc.Set("API_TOKEN", "abc")
+
+	var buf bytes.Buffer
+	rc.WriteShellExports(&buf)
+	want := "export API_TOKEN='abc'\nexport NAME='it'\\''s here'\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteShellExports() = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	rc.WriteShellExportsMasked(&buf)
+	want = "export API_TOKEN='********'\nexport NAME='it'\\''s here'\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteShellExportsMasked() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteShellExportsSkipsInvalidNames(t *testing.T) {
+	rc := NewRuntimeConfig(nil, nil)
+	for _, key := range []string{"db.host", "A;rm -rf x", "1ST", "with space", "_OK", "ok_2"} {
+		rc.Set(key, "v")
+	}
+
+	var buf bytes.Buffer
+	rc.WriteShellExports(&buf)
+	want := "export _OK='v'\nexport ok_2='v'\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteShellExports() = %q, want %q", got, want)
+	}
+}
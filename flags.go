@@ -0,0 +1,45 @@
+package runtimeconfig
+
+import (
+	"flag"
+	"strings"
+)
+
+// flagValue is a flag.Value writing straight into a RuntimeConfig key
+type flagValue struct {
+	rc  *RuntimeConfig
+	key string
+}
+
+// String returns the current value of the key, masked when the key is
+// sensitive so help output does not print secrets
+func (v *flagValue) String() string {
+	if v.rc == nil {
+		return ""
+	}
+	v.rc.mu.RLock()
+	defer v.rc.mu.RUnlock()
+	return v.rc.displayValue(v.key)
+}
+
+// Set stores the flag argument in the key
+func (v *flagValue) Set(value string) error {
+	v.rc.Set(v.key, value)
+	return nil
+}
+
+// BindFlags defines a flag on fs for every key, named as the lowercased
+// key with dashes for underscores (DB_HOST becomes -db-host) and
+// defaulting to the current value, flags passed to fs.Parse are written
+// back into the RuntimeConfig
+// note: flags that are not passed leave env loaded values untouched,
+// names already defined on fs are skipped, sensitive defaults are masked
+func (rconfig *RuntimeConfig) BindFlags(fs *flag.FlagSet) {
+	for _, key := range rconfig.sortedDataKeys() {
+		name := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+		if fs.Lookup(name) != nil {
+			continue
+		}
+		fs.Var(&flagValue{rc: rconfig, key: key}, name, "sets "+key)
+	}
+}
@@ -0,0 +1,46 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestBindFlags(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"DB_HOST", "DB_PORT"}, nil)
+	rc.SetEnvFunc(envFunc(map[string]string{"DB_HOST": "env-host", "DB_PORT": "5432"}))
+	rc.LoadValueFromEnv()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	rc.BindFlags(fs)
+	if err := fs.Parse([]string{"-db-host", "flag-host"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := rc.Get("DB_HOST"); got != "flag-host" {
+		t.Errorf("Get(DB_HOST) = %q, want the flag to win over env", got)
+	}
+	if got := rc.Get("DB_PORT"); got != "5432" {
+		t.Errorf("Get(DB_PORT) = %q, want the env value kept when the flag is not passed", got)
+	}
+	if got := fs.Lookup("db-port").DefValue; got != "5432" {
+		t.Errorf("db-port DefValue = %q, want the current value %q", got, "5432")
+	}
+}
+
+func TestBindFlagsMasksSensitive(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"DB_PASSWORD"}, nil)
+	rc.MarkSensitive("DB_PASSWORD")
+	rc.Set("DB_PASSWORD", "hunter2")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	rc.BindFlags(fs)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+
+	if out := buf.String(); strings.Contains(out, "hunter2") || !strings.Contains(out, mSensitiveMask) {
+		t.Errorf("PrintDefaults() = %q, want the sensitive default masked", out)
+	}
+}
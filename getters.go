@@ -0,0 +1,269 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// parse reads key and converts it with fn, failures are wrapped in a
+// *ParseError of the given kind so every typed getter reports alike and
+// masks sensitive values
+func parse[T any](rc *RuntimeConfig, key, kind string, fn func(value string) (T, error)) (T, error) {
+	value := rc.typedValue(key)
+	typed, err := fn(value)
+	if err != nil {
+		var zero T
+		return zero, rc.parseError(key, value, kind, err)
+	}
+	return typed, nil
+}
+
+// GetInt returns the value of key parsed as an int
+func (rconfig *RuntimeConfig) GetInt(key string) (int, error) {
+	return parse(rconfig, key, "int", strconv.Atoi)
+}
+
+// GetBool returns the value of key parsed with strconv.ParseBool
+func (rconfig *RuntimeConfig) GetBool(key string) (bool, error) {
+	return parse(rconfig, key, "bool", strconv.ParseBool)
+}
+
+// GetBoolStrict returns the value of key as a bool, only the exact
+// lowercase true and false are accepted, surrounding space aside
+func (rconfig *RuntimeConfig) GetBoolStrict(key string) (bool, error) {
+	return parse(rconfig, key, "bool", func(value string) (bool, error) {
+		switch strings.TrimSpace(value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, errors.New("expected true or false")
+	})
+}
+
+// GetFloat returns the value of key parsed as a float64
+func (rconfig *RuntimeConfig) GetFloat(key string) (float64, error) {
+	return parse(rconfig, key, "float", func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 64)
+	})
+}
+
+// GetComplex128 returns the value of key, a literal such as 1+2i, parsed
+// with strconv.ParseComplex
+func (rconfig *RuntimeConfig) GetComplex128(key string) (complex128, error) {
+	return parse(rconfig, key, "complex", func(value string) (complex128, error) {
+		return strconv.ParseComplex(value, 128)
+	})
+}
+
+// GetDuration returns the value of key parsed with time.ParseDuration
+func (rconfig *RuntimeConfig) GetDuration(key string) (time.Duration, error) {
+	return parse(rconfig, key, "duration", time.ParseDuration)
+}
+
+// GetSecondsAsDuration returns the value of key, a bare number of
+// seconds such as 30 or 1.5, as a time.Duration, NaN, Inf and values
+// beyond the range of a time.Duration are an error
+func (rconfig *RuntimeConfig) GetSecondsAsDuration(key string) (time.Duration, error) {
+	return parse(rconfig, key, "seconds", func(value string) (time.Duration, error) {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(seconds) || math.Abs(seconds) > float64(math.MaxInt64/time.Second) {
+			return 0, errors.New("expected a finite number of seconds within the range of a duration")
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	})
+}
+
+// GetPercent returns the value of key, a percentage such as 75% with
+// the sign optional, as a fraction such as 0.75, values outside 0 to 100
+// as well as NaN and Inf are an error
+func (rconfig *RuntimeConfig) GetPercent(key string) (float64, error) {
+	return parse(rconfig, key, "percent", func(value string) (float64, error) {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(percent) || math.IsInf(percent, 0) || percent < 0 || percent > 100 {
+			return 0, errors.New("expected a percentage between 0 and 100")
+		}
+		return percent / 100, nil
+	})
+}
+
+// GetEnum looks up the value of key in mapping and returns the matching
+// typed value, an error lists the accepted values when there is no match
+func GetEnum[T comparable](rc *RuntimeConfig, key string, mapping map[string]T) (T, error) {
+	return parse(rc, key, "enum", func(value string) (T, error) {
+		typed, ok := mapping[value]
+		if !ok {
+			return typed, fmt.Errorf("expected one of %s", strings.Join(sortedKeys(mapping), ","))
+		}
+		return typed, nil
+	})
+}
+
+// GetRune returns the single character held by key, an error is returned
+// if the value is empty or longer than one rune
+func (rconfig *RuntimeConfig) GetRune(key string) (rune, error) {
+	return parse(rconfig, key, "rune", func(value string) (rune, error) {
+		if utf8.RuneCountInString(value) != 1 {
+			return 0, errors.New("expected a single character")
+		}
+		r, _ := utf8.DecodeRuneInString(value)
+		return r, nil
+	})
+}
+
+// GetIP returns the value of key parsed with net.ParseIP
+func (rconfig *RuntimeConfig) GetIP(key string) (net.IP, error) {
+	return parse(rconfig, key, "ip", func(value string) (net.IP, error) {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, errors.New("invalid IP address")
+		}
+		return ip, nil
+	})
+}
+
+// GetCIDR returns the network of the value of key parsed with
+// net.ParseCIDR
+func (rconfig *RuntimeConfig) GetCIDR(key string) (*net.IPNet, error) {
+	return parse(rconfig, key, "cidr", func(value string) (*net.IPNet, error) {
+		_, ipNet, err := net.ParseCIDR(value)
+		return ipNet, err
+	})
+}
+
+// GetExistingPath returns the value of key after confirming with os.Stat
+// that the path exists
+func (rconfig *RuntimeConfig) GetExistingPath(key string) (string, error) {
+	value := rconfig.typedValue(key)
+	if _, err := os.Stat(value); err != nil {
+		return "", fmt.Errorf("key '%s' path '%s': %w", key, value, err)
+	}
+	return value, nil
+}
+
+// GetExistingDir returns the value of key after confirming with os.Stat
+// that the path exists and is a directory
+func (rconfig *RuntimeConfig) GetExistingDir(key string) (string, error) {
+	value := rconfig.typedValue(key)
+	info, err := os.Stat(value)
+	if err != nil {
+		return "", fmt.Errorf("key '%s' path '%s': %w", key, value, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("key '%s' path '%s' is not a directory", key, value)
+	}
+	return value, nil
+}
+
+// GetStringMapString parses a value like a=1,b=2 into a map, segments
+// without an = or with an empty key are skipped
+func (rconfig *RuntimeConfig) GetStringMapString(key string) map[string]string {
+	out := make(map[string]string)
+	for _, pair := range strings.Split(rconfig.typedValue(key), ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		out[k] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+// GetIntSlice returns the comma separated value of key parsed as ints,
+// the error names the first element that fails to parse, an empty value
+// yields an empty slice
+func (rconfig *RuntimeConfig) GetIntSlice(key string) ([]int, error) {
+	elements := splitList(rconfig.typedValue(key))
+	ints := make([]int, 0, len(elements))
+	for _, element := range elements {
+		i, err := strconv.Atoi(element)
+		if err != nil {
+			return nil, rconfig.parseError(key, element, "int", err)
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
+
+// GetDurationSlice returns the comma separated value of key parsed with
+// time.ParseDuration, the error names the first element that fails to
+// parse, an empty value yields an empty slice
+func (rconfig *RuntimeConfig) GetDurationSlice(key string) ([]time.Duration, error) {
+	elements := splitList(rconfig.typedValue(key))
+	durations := make([]time.Duration, 0, len(elements))
+	for _, element := range elements {
+		d, err := time.ParseDuration(element)
+		if err != nil {
+			return nil, rconfig.parseError(key, element, "duration", err)
+		}
+		durations = append(durations, d)
+	}
+	return durations, nil
+}
+
+// GetStringSet returns the comma separated value of key trimmed, with
+// empty elements and duplicates dropped, keeping first seen order
+func (rconfig *RuntimeConfig) GetStringSet(key string) []string {
+	seen := make(map[string]bool)
+	var set []string
+	for _, element := range splitList(rconfig.typedValue(key)) {
+		if element == "" || seen[element] {
+			continue
+		}
+		seen[element] = true
+		set = append(set, element)
+	}
+	return set
+}
+
+// splitList splits a comma separated value and trims each element, an
+// empty value yields no elements
+func splitList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	elements := strings.Split(value, ",")
+	for i, element := range elements {
+		elements[i] = strings.TrimSpace(element)
+	}
+	return elements
+}
+
+// unescaper expands the escape sequences allowed in double quoted values
+var unescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`)
+
+// GetUnquoted returns the value of key with one pair of matching single
+// or double quotes removed, escape sequences such as \n and \" are
+// expanded inside double quotes while single quoted values are literal
+func (rconfig *RuntimeConfig) GetUnquoted(key string) string {
+	return unquote(rconfig.Get(key))
+}
+
+// unquote strips a matching pair of surrounding quotes from value
+func unquote(value string) string {
+	if len(value) < 2 || value[0] != value[len(value)-1] {
+		return value
+	}
+	switch value[0] {
+	case '"':
+		return unescaper.Replace(value[1 : len(value)-1])
+	case '\'':
+		return value[1 : len(value)-1]
+	}
+	return value
+}
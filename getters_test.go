@@ -0,0 +1,391 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetEnum(t *testing.T) {
+	type level int
+	levels := map[string]level{"debug": 0, "info": 1, "warn": 2}
+	names := map[string]string{"s": "small", "l": "large"}
+
+	rc := NewRuntimeConfig([]string{"LEVEL", "SIZE"}, nil)
+	rc.Set("LEVEL", "warn")
+	rc.Set("SIZE", "l")
+
+	if got, err := GetEnum(rc, "LEVEL", levels); err != nil || got != 2 {
+		t.Errorf("GetEnum(LEVEL) = %v, %v, want 2, nil", got, err)
+	}
+	if got, err := GetEnum(rc, "SIZE", names); err != nil || got != "large" {
+		t.Errorf("GetEnum(SIZE) = %q, %v, want %q, nil", got, err, "large")
+	}
+
+	rc.Set("LEVEL", "trace")
+	_, err := GetEnum(rc, "LEVEL", levels)
+	if err == nil {
+		t.Fatal("GetEnum() with an unknown value returned no error")
+	}
+	if !strings.Contains(err.Error(), "debug,info,warn") {
+		t.Errorf("GetEnum() error = %q, want it to list the accepted values", err)
+	}
+}
+
+func TestGetRune(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    rune
+		wantErr bool
+	}{
+		{"ascii", ",", ',', false},
+		{"multibyte", "é", 'é', false},
+		{"empty", "", 0, true},
+		{"too long", "ab", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"SEP"}, nil)
+			rc.Set("SEP", tt.value)
+			got, err := rc.GetRune("SEP")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("GetRune() = %q, %v, want %q, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"ipv4", "192.168.1.10", "192.168.1.10", false},
+		{"ipv6", "::1", "::1", false},
+		{"invalid", "not-an-ip", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"ADDR"}, nil)
+			rc.Set("ADDR", tt.value)
+			ip, err := rc.GetIP("ADDR")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && ip.String() != tt.want {
+				t.Errorf("GetIP() = %v, want %v", ip, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCIDR(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"NET"}, nil)
+	rc.Set("NET", "10.0.0.7/8")
+	ipNet, err := rc.GetCIDR("NET")
+	if err != nil || ipNet.String() != "10.0.0.0/8" {
+		t.Errorf("GetCIDR() = %v, %v, want 10.0.0.0/8, nil", ipNet, err)
+	}
+
+	rc.Set("NET", "10.0.0.7")
+	if _, err := rc.GetCIDR("NET"); err == nil {
+		t.Error("GetCIDR() without a prefix length returned no error")
+	}
+}
+
+func TestGetExistingPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rc := NewRuntimeConfig([]string{"PATH_KEY"}, nil)
+	for _, path := range []string{dir, file} {
+		rc.Set("PATH_KEY", path)
+		if got, err := rc.GetExistingPath("PATH_KEY"); err != nil || got != path {
+			t.Errorf("GetExistingPath() = %q, %v, want %q, nil", got, err, path)
+		}
+	}
+
+	rc.Set("PATH_KEY", filepath.Join(dir, "missing"))
+	if _, err := rc.GetExistingPath("PATH_KEY"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("GetExistingPath() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestGetExistingDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rc := NewRuntimeConfig([]string{"DIR"}, nil)
+	rc.Set("DIR", dir)
+	if got, err := rc.GetExistingDir("DIR"); err != nil || got != dir {
+		t.Errorf("GetExistingDir() = %q, %v, want %q, nil", got, err, dir)
+	}
+
+	rc.Set("DIR", file)
+	if _, err := rc.GetExistingDir("DIR"); err == nil {
+		t.Error("GetExistingDir() on a file returned no error")
+	}
+
+	rc.Set("DIR", filepath.Join(dir, "missing"))
+	if _, err := rc.GetExistingDir("DIR"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("GetExistingDir() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestGetStringMapString(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]string
+	}{
+		{"well formed", "a=1, b = 2", map[string]string{"a": "1", "b": "2"}},
+		{"empty", "", map[string]string{}},
+		{"malformed", "a=1,novalue,=2,c=", map[string]string{"a": "1", "c": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"LABELS"}, nil)
+			rc.Set("LABELS", tt.value)
+			if got := rc.GetStringMapString("LABELS"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStringMapString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSecondsAsDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"integer", "30", 30 * time.Second, false},
+		{"fractional", "1.5", 1500 * time.Millisecond, false},
+		{"bad", "30s", 0, true},
+		{"negative", "-2", -2 * time.Second, false},
+		{"nan", "NaN", 0, true},
+		{"inf", "Inf", 0, true},
+		{"negative inf", "-Inf", 0, true},
+		{"overflow", "1e12", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"TIMEOUT"}, nil)
+			rc.Set("TIMEOUT", tt.value)
+			got, err := rc.GetSecondsAsDuration("TIMEOUT")
+			if (err != nil) != tt.wantErr || (err == nil && got != tt.want) {
+				t.Errorf("GetSecondsAsDuration() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetIntSlice(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORTS"}, nil)
+	rc.Set("PORTS", "80, 443,8080")
+	if got, err := rc.GetIntSlice("PORTS"); err != nil || !reflect.DeepEqual(got, []int{80, 443, 8080}) {
+		t.Errorf("GetIntSlice() = %v, %v, want [80 443 8080], nil", got, err)
+	}
+
+	rc.Set("PORTS", "80,http,443")
+	_, err := rc.GetIntSlice("PORTS")
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Value != "http" {
+		t.Errorf("GetIntSlice() error = %v, want a *ParseError naming http", err)
+	}
+
+	rc.Set("PORTS", "")
+	if got, err := rc.GetIntSlice("PORTS"); err != nil || len(got) != 0 {
+		t.Errorf("GetIntSlice() of empty = %v, %v, want an empty slice", got, err)
+	}
+}
+
+func TestGetDurationSlice(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"BACKOFF"}, nil)
+	rc.Set("BACKOFF", "100ms, 1s,1m")
+	want := []time.Duration{100 * time.Millisecond, time.Second, time.Minute}
+	if got, err := rc.GetDurationSlice("BACKOFF"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDurationSlice() = %v, %v, want %v, nil", got, err, want)
+	}
+
+	rc.Set("BACKOFF", "1s,soon")
+	_, err := rc.GetDurationSlice("BACKOFF")
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Value != "soon" || pe.Kind != "duration" {
+		t.Errorf("GetDurationSlice() error = %v, want a duration *ParseError naming soon", err)
+	}
+}
+
+func TestTypedGetterErrorShape(t *testing.T) {
+	getters := []struct {
+		kind string
+		get  func(rc *RuntimeConfig) error
+	}{
+		{"int", func(rc *RuntimeConfig) error { _, err := rc.GetInt("KEY"); return err }},
+		{"bool", func(rc *RuntimeConfig) error { _, err := rc.GetBool("KEY"); return err }},
+		{"bool", func(rc *RuntimeConfig) error { _, err := rc.GetBoolStrict("KEY"); return err }},
+		{"float", func(rc *RuntimeConfig) error { _, err := rc.GetFloat("KEY"); return err }},
+		{"complex", func(rc *RuntimeConfig) error { _, err := rc.GetComplex128("KEY"); return err }},
+		{"duration", func(rc *RuntimeConfig) error { _, err := rc.GetDuration("KEY"); return err }},
+		{"seconds", func(rc *RuntimeConfig) error { _, err := rc.GetSecondsAsDuration("KEY"); return err }},
+		{"percent", func(rc *RuntimeConfig) error { _, err := rc.GetPercent("KEY"); return err }},
+		{"enum", func(rc *RuntimeConfig) error { _, err := GetEnum(rc, "KEY", map[string]int{"a": 1}); return err }},
+		{"rune", func(rc *RuntimeConfig) error { _, err := rc.GetRune("KEY"); return err }},
+		{"ip", func(rc *RuntimeConfig) error { _, err := rc.GetIP("KEY"); return err }},
+		{"cidr", func(rc *RuntimeConfig) error { _, err := rc.GetCIDR("KEY"); return err }},
+	}
+	for _, g := range getters {
+		t.Run(g.kind, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"KEY"}, nil)
+			rc.Set("KEY", "garbage")
+			err := g.get(rc)
+
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("error = %v, want a *ParseError", err)
+			}
+			if pe.Key != "KEY" || pe.Value != "garbage" || pe.Kind != g.kind || pe.Err == nil {
+				t.Errorf("ParseError = %+v, want Key KEY, Value garbage, Kind %s", pe, g.kind)
+			}
+			if prefix := "key 'KEY' has invalid " + g.kind + " value 'garbage': "; !strings.HasPrefix(err.Error(), prefix) {
+				t.Errorf("Error() = %q, want prefix %q", err, prefix)
+			}
+		})
+	}
+}
+
+func TestGetUnquoted(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"double", `"hello world"`, "hello world"},
+		{"single", `'it\n'`, `it\n`},
+		{"unquoted", "plain", "plain"},
+		{"escaped", `"line\n\"quoted\"\ttab\\"`, "line\n\"quoted\"\ttab\\"},
+		{"mismatched", `"open'`, `"open'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"MSG"}, nil)
+			rc.Set("MSG", tt.value)
+			if got := rc.GetUnquoted("MSG"); got != tt.want {
+				t.Errorf("GetUnquoted() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPercent(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{"75%", 0.75, false},
+		{"75", 0.75, false},
+		{"100%", 1, false},
+		{"0%", 0, false},
+		{"150%", 0, true},
+		{"-5%", 0, true},
+		{"lots", 0, true},
+		{"NaN%", 0, true},
+		{"Inf%", 0, true},
+		{"-Inf", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"RATE"}, nil)
+			rc.Set("RATE", tt.value)
+			got, err := rc.GetPercent("RATE")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("GetPercent() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetStringSet(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"duplicates", "b, a,b,,a ,c", []string{"b", "a", "c"}},
+		{"no duplicates", "x,y,z", []string{"x", "y", "z"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"TAGS"}, nil)
+			rc.Set("TAGS", tt.value)
+			if got := rc.GetStringSet("TAGS"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStringSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetComplex128(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    complex128
+		wantErr bool
+	}{
+		{"1+2i", complex(1, 2), false},
+		{"3.5", complex(3.5, 0), false},
+		{"garbage", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"Z"}, nil)
+			rc.Set("Z", tt.value)
+			got, err := rc.GetComplex128("Z")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("GetComplex128() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetBoolStrict(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{"true", true, false},
+		{"false", false, false},
+		{" true ", true, false},
+		{"TRUE", false, true},
+		{"1", false, true},
+		{"yes", false, true},
+		{"", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"FLAG"}, nil)
+			rc.Set("FLAG", tt.value)
+			got, err := rc.GetBoolStrict("FLAG")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("GetBoolStrict() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// mIncludePrefix package const marking a value as a reference into
+// another RuntimeConfig
+const mIncludePrefix string = "@include:"
+
+// ResolveIncludes replaces every value of the form @include:source.KEY
+// with the value of KEY in sources[source], unknown sources or keys are
+// reported together and nothing is replaced when any reference fails,
+// a nil source counts as unknown
+// note: the source name ends at the first ".", KEY may contain more
+// and goes through the key normalizer of the source
+func (rconfig *RuntimeConfig) ResolveIncludes(sources map[string]*RuntimeConfig) error {
+	rconfig.mu.RLock()
+	refs := make(map[string]string)
+	for key, value := range rconfig.data {
+		if ref, ok := strings.CutPrefix(value, mIncludePrefix); ok {
+			refs[key] = ref
+		}
+	}
+	rconfig.mu.RUnlock()
+
+	var errs []error
+	values := make(map[string]string, len(refs))
+	for _, key := range sortedKeys(refs) {
+		name, refKey, _ := strings.Cut(refs[key], ".")
+		source := sources[name]
+		if source == nil {
+			errs = append(errs, fmt.Errorf("key '%s' includes unknown source '%s'", key, name))
+			continue
+		}
+		source.mu.RLock()
+		value, ok := source.data[source.canonical(refKey)]
+		source.mu.RUnlock()
+		if !ok {
+			errs = append(errs, fmt.Errorf("key '%s' includes unknown key '%s' of source '%s'", key, refKey, name))
+			continue
+		}
+		values[key] = value
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	rconfig.applyValues(values)
+	return nil
+}
@@ -0,0 +1,69 @@
+package runtimeconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveIncludes(t *testing.T) {
+	shared := NewRuntimeConfig([]string{"DB_HOST", "DB.PORT"}, nil)
+	shared.Set("DB_HOST", "db.internal")
+	shared.Set("DB.PORT", "5432")
+
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "NAME"}, nil)
+	rc.Set("HOST", "@include:shared.DB_HOST")
+	rc.Set("PORT", "@include:shared.DB.PORT")
+	rc.Set("NAME", "api")
+
+	if err := rc.ResolveIncludes(map[string]*RuntimeConfig{"shared": shared}); err != nil {
+		t.Fatalf("ResolveIncludes() error = %v", err)
+	}
+	for key, want := range map[string]string{"HOST": "db.internal", "PORT": "5432", "NAME": "api"} {
+		if got := rc.Get(key); got != want {
+			t.Errorf("Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		sources map[string]*RuntimeConfig
+		wantErr string
+	}{
+		{"unknown source", "@include:other.DB_HOST", map[string]*RuntimeConfig{"shared": shared}, "unknown source 'other'"},
+		{"nil source", "@include:shared.DB_HOST", map[string]*RuntimeConfig{"shared": nil}, "unknown source 'shared'"},
+		{"unknown key", "@include:shared.MISSING", map[string]*RuntimeConfig{"shared": shared}, "unknown key 'MISSING'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+			rc.Set("HOST", tt.ref)
+			rc.Set("PORT", "@include:shared.DB.PORT")
+
+			err := rc.ResolveIncludes(tt.sources)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ResolveIncludes() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if got := rc.Get("PORT"); got != "@include:shared.DB.PORT" {
+				t.Errorf("Get(PORT) = %q, want it left unresolved after a failure", got)
+			}
+		})
+	}
+}
+
+func TestResolveIncludesNormalizesSourceKeys(t *testing.T) {
+	shared := NewRuntimeConfig([]string{"DB_HOST"}, nil)
+	shared.SetKeyNormalizer(func(key string) string {
+		return strings.ReplaceAll(strings.ToUpper(key), "-", "_")
+	})
+	shared.Set("DB_HOST", "db.internal")
+
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	rc.Set("HOST", "@include:shared.db-host")
+	if err := rc.ResolveIncludes(map[string]*RuntimeConfig{"shared": shared}); err != nil {
+		t.Fatalf("ResolveIncludes() error = %v", err)
+	}
+	if got := rc.Get("HOST"); got != "db.internal" {
+		t.Errorf("Get(HOST) = %q, want %q", got, "db.internal")
+	}
+}
@@ -0,0 +1,72 @@
+package runtimeconfig
+
+import "encoding/json"
+
+// fullJSON is the document produced by MarshalJSONFull
+type fullJSON struct {
+	Data       map[string]string `json:"data"`
+	IgnoreKeys []string          `json:"ignoreKeys"`
+	Defaults   map[string]string `json:"defaults"`
+}
+
+// MarshalJSON encodes the data prop as a JSON object
+func (rconfig *RuntimeConfig) MarshalJSON() ([]byte, error) {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return json.Marshal(rconfig.data)
+}
+
+// UnmarshalJSON sets the key value pairs of a JSON object in the data prop
+func (rconfig *RuntimeConfig) UnmarshalJSON(b []byte) error {
+	var data map[string]string
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range data {
+		rconfig.write(&changes, rconfig.canonical(key), value)
+	}
+	return nil
+}
+
+// MarshalJSONFull encodes the data prop along with the sorted ignore
+// keys and the registered defaults so a round-trip keeps them intact
+func (rconfig *RuntimeConfig) MarshalJSONFull() ([]byte, error) {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return json.Marshal(fullJSON{
+		Data:       rconfig.data,
+		IgnoreKeys: sortedKeys(rconfig.ignoreKeys),
+		Defaults:   rconfig.defaults,
+	})
+}
+
+// UnmarshalJSONFull restores a document written by MarshalJSONFull,
+// values are set, ignore keys added and defaults registered
+func (rconfig *RuntimeConfig) UnmarshalJSONFull(b []byte) error {
+	var doc fullJSON
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range doc.Data {
+		rconfig.write(&changes, rconfig.canonical(key), value)
+	}
+	if !rconfig.frozenIgnore {
+		for _, key := range doc.IgnoreKeys {
+			rconfig.setIgnore(rconfig.canonical(key), true)
+		}
+	}
+	for key, value := range doc.Defaults {
+		rconfig.defaults[rconfig.canonical(key)] = value
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarshalJSONFullRoundTrip(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "DEBUG"}, []string{"DEBUG"})
+	rc.Set("HOST", "example.com")
+	rc.SetDefault("PORT", "8080")
+
+	b, err := rc.MarshalJSONFull()
+	if err != nil {
+		t.Fatalf("MarshalJSONFull() error = %v", err)
+	}
+	restored := NewRuntimeConfig(nil, nil)
+	if err := restored.UnmarshalJSONFull(b); err != nil {
+		t.Fatalf("UnmarshalJSONFull() error = %v", err)
+	}
+
+	if got, want := restored.Entries(), rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored Entries() = %v, want %v", got, want)
+	}
+	if got := restored.IgnoreKeys(); !reflect.DeepEqual(got, []string{"DEBUG"}) {
+		t.Errorf("restored IgnoreKeys() = %v, want [DEBUG]", got)
+	}
+	restored.Set("PORT", "9090")
+	if !restored.ResetToDefault("PORT") || restored.Get("PORT") != "8080" {
+		t.Errorf("restored default of PORT = %q, want %q", restored.Get("PORT"), "8080")
+	}
+}
@@ -0,0 +1,92 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// UpdateExisting sets the values from m for keys already present in the
+// data prop and returns the sorted keys of m that were rejected as unknown
+func (rconfig *RuntimeConfig) UpdateExisting(m map[string]string) []string {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+
+	var rejected []string
+	for key, value := range m {
+		canonical := rconfig.canonical(key)
+		if _, ok := rconfig.data[canonical]; !ok {
+			rejected = append(rejected, key)
+			continue
+		}
+		rconfig.write(&changes, canonical, value)
+	}
+	slices.Sort(rejected)
+	return rejected
+}
+
+// LoadFromMapTrimPrefix stores the entries of m with prefix stripped
+// from their keys, keys without the prefix are stored as-is
+func (rconfig *RuntimeConfig) LoadFromMapTrimPrefix(m map[string]string, prefix string) {
+	rconfig.loadTrimmed(m, prefix, false)
+}
+
+// LoadFromMapOnlyPrefix stores the entries of m with prefix stripped
+// from their keys, keys without the prefix are skipped
+func (rconfig *RuntimeConfig) LoadFromMapOnlyPrefix(m map[string]string, prefix string) {
+	rconfig.loadTrimmed(m, prefix, true)
+}
+
+// loadTrimmed stores m with prefix stripped, optionally skipping keys
+// that lack the prefix
+func (rconfig *RuntimeConfig) loadTrimmed(m map[string]string, prefix string, skipUnprefixed bool) {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range m {
+		trimmed, ok := strings.CutPrefix(key, prefix)
+		if !ok && skipUnprefixed {
+			continue
+		}
+		rconfig.write(&changes, rconfig.canonical(trimmed), value)
+	}
+}
+
+// LoadFromValues stores the first value of each entry in v, such as a
+// parsed query string or form, for keys already present in the data prop
+// note: unknown keys and entries without values are skipped
+func (rconfig *RuntimeConfig) LoadFromValues(v url.Values) {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, values := range v {
+		key = rconfig.canonical(key)
+		if _, ok := rconfig.data[key]; !ok || len(values) == 0 {
+			continue
+		}
+		rconfig.write(&changes, key, values[0])
+	}
+}
+
+// LoadProfile stores overrides[profile] on top of the current values,
+// e.g. the dev or prod entry, an error is returned for an unknown profile
+func (rconfig *RuntimeConfig) LoadProfile(profile string, overrides map[string]map[string]string) error {
+	values, ok := overrides[profile]
+	if !ok {
+		return fmt.Errorf("unknown profile '%s', expected one of %s", profile, strings.Join(sortedKeys(overrides), ","))
+	}
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range values {
+		rconfig.write(&changes, rconfig.canonical(key), value)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package runtimeconfig
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateExisting(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	rejected := rc.UpdateExisting(map[string]string{
+		"HOST":    "example.com",
+		"PORT":    "8080",
+		"UNKNOWN": "x",
+		"OTHER":   "y",
+	})
+
+	if want := []string{"OTHER", "UNKNOWN"}; !reflect.DeepEqual(rejected, want) {
+		t.Errorf("UpdateExisting() rejected = %v, want %v", rejected, want)
+	}
+	if got := rc.Get("HOST"); got != "example.com" {
+		t.Errorf("Get(HOST) = %q, want %q", got, "example.com")
+	}
+	if got := rc.Get("PORT"); got != "8080" {
+		t.Errorf("Get(PORT) = %q, want %q", got, "8080")
+	}
+	if rc.Has("UNKNOWN") || rc.Has("OTHER") {
+		t.Error("UpdateExisting() added unknown keys")
+	}
+}
+
+func TestLoadFromMapTrimPrefix(t *testing.T) {
+	rc := NewRuntimeConfig(nil, nil)
+	rc.LoadFromMapTrimPrefix(map[string]string{"APP_HOST": "example.com", "PORT": "8080"}, "APP_")
+
+	want := []string{"HOST=example.com", "PORT=8080"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadFromValues(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "TAG", "EMPTY"}, nil)
+	rc.LoadFromValues(url.Values{
+		"HOST":    {"example.com"},
+		"TAG":     {"first", "second"},
+		"EMPTY":   {},
+		"UNKNOWN": {"x"},
+	})
+
+	want := []string{"EMPTY=", "HOST=example.com", "TAG=first"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	overrides := map[string]map[string]string{
+		"dev":  {"HOST": "localhost", "DEBUG": "true"},
+		"prod": {"HOST": "example.com"},
+	}
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	rc.Set("PORT", "8080")
+	if err := rc.LoadProfile("prod", overrides); err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+
+	want := []string{"HOST=example.com", "PORT=8080"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v without dev values leaking", got, want)
+	}
+
+	if err := rc.LoadProfile("staging", overrides); err == nil {
+		t.Error("LoadProfile() of an unknown profile returned no error")
+	}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v after an unknown profile, want %v", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+package runtimeconfig
+
+// UnionKeys returns the sorted set of data keys across all configs,
+// nil configs are skipped
+func UnionKeys(configs ...*RuntimeConfig) []string {
+	union := make(map[string]bool)
+	for _, rc := range configs {
+		if rc == nil {
+			continue
+		}
+		for _, key := range rc.Keys() {
+			union[key] = true
+		}
+	}
+	return sortedKeys(union)
+}
@@ -0,0 +1,19 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnionKeys(t *testing.T) {
+	a := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	b := NewRuntimeConfig([]string{"PORT", "USER"}, nil)
+	c := NewRuntimeConfig([]string{"DEBUG"}, nil)
+
+	if got, want := UnionKeys(a, b), []string{"HOST", "PORT", "USER"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnionKeys(overlapping) = %v, want %v", got, want)
+	}
+	if got, want := UnionKeys(a, nil, c), []string{"DEBUG", "HOST", "PORT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnionKeys(disjoint) = %v, want %v", got, want)
+	}
+}
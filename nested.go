@@ -0,0 +1,166 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Nested expands the flat keys of the data prop into a nested map by
+// splitting them on delim
+// note: a key that is also the prefix of deeper keys is dropped in
+// favour of the nested values
+func (rconfig *RuntimeConfig) Nested(delim string) map[string]interface{} {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	root := make(map[string]interface{})
+	for _, key := range sortedKeys(rconfig.data) {
+		parts := []string{key}
+		if delim != "" {
+			parts = strings.Split(key, delim)
+		}
+
+		node := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		leaf := parts[len(parts)-1]
+		if _, ok := node[leaf].(map[string]interface{}); !ok {
+			node[leaf] = rconfig.data[key]
+		}
+	}
+	return root
+}
+
+// GroupByPrefix groups the keys of the data prop by their first segment
+// before delim, e.g. DB or REDIS, keys without delim fall into the ""
+// group, each group is sorted
+func (rconfig *RuntimeConfig) GroupByPrefix(delim string) map[string][]string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	groups := make(map[string][]string)
+	for _, key := range sortedKeys(rconfig.data) {
+		prefix, _, ok := strings.Cut(key, delim)
+		if !ok || delim == "" {
+			prefix = ""
+		}
+		groups[prefix] = append(groups[prefix], key)
+	}
+	return groups
+}
+
+// SetKeyDelimiter sets the separator GetPath uses to join nested key
+// segments, the default is "."
+func (rconfig *RuntimeConfig) SetKeyDelimiter(delim string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.keyDelim = delim
+}
+
+// GetPath resolves a slash separated path such as db/host against the
+// flat keys joined with the key delimiter (db.host), a leading slash is
+// allowed, ok reports whether the key exists
+// note: a trailing slash addresses an empty segment and so only
+// matches a key ending in the delimiter
+func (rconfig *RuntimeConfig) GetPath(ptr string) (value string, ok bool) {
+	rconfig.mu.RLock()
+	key := rconfig.canonical(strings.Join(strings.Split(strings.TrimPrefix(ptr, "/"), "/"), rconfig.keyDelim))
+	value, ok = rconfig.data[key]
+	rconfig.mu.RUnlock()
+
+	if ok {
+		rconfig.markAccessed(key)
+	}
+	return value, ok
+}
+
+// LoadNested flattens m into the data prop joining nested keys with
+// delim, leaf values are converted with fmt.Sprint
+func (rconfig *RuntimeConfig) LoadNested(m map[string]interface{}, delim string) {
+	flat := make(map[string]string)
+	flatten(flat, "", m, delim)
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range flat {
+		rconfig.write(&changes, rconfig.canonical(key), value)
+	}
+}
+
+// flatten writes the leaves of m into out, prefixing keys with prefix
+func flatten(out map[string]string, prefix string, m map[string]interface{}, delim string) {
+	for key, value := range m {
+		if prefix != "" {
+			key = prefix + delim + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(out, key, v, delim)
+		case map[string]string:
+			for child, leaf := range v {
+				out[key+delim+child] = leaf
+			}
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// DeepMerge copies the keys of other into the data prop treating them
+// as nested on delim, sibling keys such as db.host and db.port from
+// either side are kept while a leaf set on both takes the value of other
+// note: where other holds a leaf in place of one of our subtrees, or a
+// subtree in place of one of our leaves, the shape of other wins, keys
+// of other go through our key normalizer
+func (rconfig *RuntimeConfig) DeepMerge(other *RuntimeConfig, delim string) {
+	if other == rconfig {
+		return
+	}
+	other.mu.RLock()
+	incoming := make(map[string]string, len(other.data))
+	for key, value := range other.data {
+		incoming[key] = value
+	}
+	other.mu.RUnlock()
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	incoming = rekeyMap(incoming, rconfig.canonical)
+	for _, key := range sortedKeys(incoming) {
+		if delim != "" {
+			rconfig.removeConflicts(&changes, key, delim, incoming)
+		}
+		rconfig.write(&changes, key, incoming[key])
+	}
+}
+
+// removeConflicts removes our keys nested below key and our leaves at a
+// parent of key unless incoming holds them too, callers must hold the
+// write lock
+func (rconfig *RuntimeConfig) removeConflicts(changes *[]change, key, delim string, incoming map[string]string) {
+	for existing := range rconfig.data {
+		if _, ok := incoming[existing]; !ok && strings.HasPrefix(existing, key+delim) {
+			rconfig.remove(changes, existing)
+		}
+	}
+	parts := strings.Split(key, delim)
+	for i := 1; i < len(parts); i++ {
+		parent := strings.Join(parts[:i], delim)
+		if _, ok := incoming[parent]; ok {
+			continue
+		}
+		if _, ok := rconfig.data[parent]; ok {
+			rconfig.remove(changes, parent)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNestedRoundTrip(t *testing.T) {
+	in := map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": "5432",
+		},
+		"name": "app",
+	}
+	rc := NewRuntimeConfig(nil, nil)
+	rc.LoadNested(in, ".")
+
+	if got := rc.Get("db.host"); got != "localhost" {
+		t.Errorf("Get(db.host) = %q, want %q", got, "localhost")
+	}
+	if got := rc.Nested("."); !reflect.DeepEqual(got, in) {
+		t.Errorf("Nested() = %v, want %v", got, in)
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"db.host", "db."}, nil)
+	rc.Set("db.host", "localhost")
+	rc.Set("db.", "trailing")
+
+	tests := []struct {
+		path   string
+		want   string
+		wantOk bool
+	}{
+		{"db/host", "localhost", true},
+		{"/db/host", "localhost", true},
+		{"db/port", "", false},
+		{"db/", "trailing", true},
+	}
+	for _, tt := range tests {
+		if got, ok := rc.GetPath(tt.path); got != tt.want || ok != tt.wantOk {
+			t.Errorf("GetPath(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	rc.SetKeyDelimiter("__")
+	rc.Set("db__host", "other")
+	if got, ok := rc.GetPath("db/host"); got != "other" || !ok {
+		t.Errorf("GetPath() with delimiter __ = %q, %v, want %q, true", got, ok, "other")
+	}
+}
+
+func TestDeepMerge(t *testing.T) {
+	rc := NewRuntimeConfig(nil, nil)
+	rc.Set("db.host", "localhost")
+	rc.Set("db.port", "5432")
+	rc.Set("cache.ttl", "60")
+
+	other := NewRuntimeConfig(nil, nil)
+	other.Set("db.port", "6543")
+	other.Set("db.user", "admin")
+	other.Set("log.level", "debug")
+	rc.DeepMerge(other, ".")
+
+	want := []string{"cache.ttl=60", "db.host=localhost", "db.port=6543", "db.user=admin", "log.level=debug"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want both subtrees merged %v", got, want)
+	}
+}
+
+func TestDeepMergeNormalizesKeys(t *testing.T) {
+	rc := NewRuntimeConfig(nil, nil)
+	rc.SetKeyNormalizer(strings.ToUpper)
+	rc.Set("db.host", "localhost")
+
+	other := NewRuntimeConfig(nil, nil)
+	other.Set("db.host", "db.internal")
+	other.Set("db.port", "5432")
+	rc.DeepMerge(other, ".")
+
+	want := []string{"DB.HOST=db.internal", "DB.PORT=5432"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+	if got := rc.Get("db.port"); got != "5432" {
+		t.Errorf("Get(db.port) = %q, want %q", got, "5432")
+	}
+}
+
+func TestGroupByPrefix(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"DB_HOST", "DB_PORT", "CACHE_TTL", "DEBUG"}, nil)
+
+	want := map[string][]string{
+		"DB":    {"DB_HOST", "DB_PORT"},
+		"CACHE": {"CACHE_TTL"},
+		"":      {"DEBUG"},
+	}
+	if got := rc.GroupByPrefix("_"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByPrefix(_) = %v, want %v", got, want)
+	}
+
+	want = map[string][]string{"": {"CACHE_TTL", "DB_HOST", "DB_PORT", "DEBUG"}}
+	if got := rc.GroupByPrefix(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByPrefix() with no delimiter = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package runtimeconfig
+
+import "strings"
+
+// NormalizeKeysUpper renames every key, including ignore keys and key
+// metadata, to upper case
+// note: colliding keys are merged in sorted order of their original
+// names, so the last one wins, frozen ignore keys are renamed too so
+// the same keys stay ignored
+func (rconfig *RuntimeConfig) NormalizeKeysUpper() {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.rekey(strings.ToUpper)
+}
+
+// NormalizeKeysLower renames every key, including ignore keys and key
+// metadata, to lower case
+// note: colliding keys are merged in sorted order of their original
+// names, so the last one wins, frozen ignore keys are renamed too so
+// the same keys stay ignored
+func (rconfig *RuntimeConfig) NormalizeKeysLower() {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.rekey(strings.ToLower)
+}
+
+// SetKeyNormalizer maps every key passed to a method, and every key
+// of the maps given to the loaders, through fn, e.g. to upper case them
+// and replace dashes with underscores, so any spelling reaches the same
+// canonical key which is also the name looked up in the env, passing
+// nil removes it
+// note: existing keys and key metadata are re-keyed with fn right away,
+// colliding keys are merged as in NormalizeKeysUpper
+func (rconfig *RuntimeConfig) SetKeyNormalizer(fn func(key string) string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.normalizer = fn
+	if fn != nil {
+		rconfig.rekey(fn)
+	}
+}
+
+// canonical returns key mapped through the key normalizer, callers must
+// hold the lock
+func (rconfig *RuntimeConfig) canonical(key string) string {
+	if rconfig.normalizer == nil {
+		return key
+	}
+	return rconfig.normalizer(key)
+}
+
+// rekey renames every key held by the RuntimeConfig with fn, callers
+// must hold the write lock
+func (rconfig *RuntimeConfig) rekey(fn func(key string) string) {
+	rconfig.data = rekeyMap(rconfig.data, fn)
+	rconfig.ignoreKeys = rekeyMap(rconfig.ignoreKeys, fn)
+	rconfig.shared = false
+	rconfig.required = rekeyMap(rconfig.required, fn)
+	rconfig.requiredIf = rekeyMap(rconfig.requiredIf, fn)
+	for key, conds := range rconfig.requiredIf {
+		renamed := make([]condition, len(conds))
+		for i, c := range conds {
+			renamed[i] = condition{key: fn(c.key), value: c.value}
+		}
+		rconfig.requiredIf[key] = renamed
+	}
+	rconfig.defaults = rekeyMap(rconfig.defaults, fn)
+	rconfig.sensitive = rekeyMap(rconfig.sensitive, fn)
+	rconfig.allowed = rekeyMap(rconfig.allowed, fn)
+	rconfig.patterns = rekeyMap(rconfig.patterns, fn)
+	rconfig.lengths = rekeyMap(rconfig.lengths, fn)
+	rconfig.validators = rekeyMap(rconfig.validators, fn)
+	rconfig.types = rekeyMap(rconfig.types, fn)
+	rconfig.aliases = rekeyMap(rconfig.aliases, fn)
+	rconfig.sources = rekeyMap(rconfig.sources, fn)
+	rconfig.expires = rekeyMap(rconfig.expires, fn)
+	rconfig.ttls = rekeyMap(rconfig.ttls, fn)
+	rconfig.derived = rekeyMap(rconfig.derived, fn)
+	rconfig.lazyDone = rekeyMap(rconfig.lazyDone, fn)
+	rconfig.missCache = rekeyMap(rconfig.missCache, fn)
+	for i, group := range rconfig.exclusive {
+		renamed := make([]string, len(group))
+		for j, key := range group {
+			renamed[j] = fn(key)
+		}
+		rconfig.exclusive[i] = renamed
+	}
+}
+
+// rekeyMap returns a copy of m with every key renamed by fn, keys are
+// visited in sorted order so later keys win on collision
+func rekeyMap[V any](m map[string]V, fn func(key string) string) map[string]V {
+	out := make(map[string]V, len(m))
+	for _, key := range sortedKeys(m) {
+		out[fn(key)] = m[key]
+	}
+	return out
+}
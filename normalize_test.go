@@ -0,0 +1,114 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"db_host", "Port"}, []string{"debug"})
+	rc.Set("db_host", "localhost")
+	rc.NormalizeKeysUpper()
+
+	if got, want := rc.Entries(), []string{"DB_HOST=localhost", "PORT="}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() after NormalizeKeysUpper = %v, want %v", got, want)
+	}
+	if !rc.IsIgnored("DEBUG") {
+		t.Error("IsIgnored(DEBUG) = false, want the ignore key renamed")
+	}
+
+	rc.NormalizeKeysLower()
+	if got, want := rc.Entries(), []string{"db_host=localhost", "port="}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() after NormalizeKeysLower = %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeKeysCollision(t *testing.T) {
+	rc := NewRuntimeConfig(nil, nil)
+	rc.Set("DB_HOST", "upper")
+	rc.Set("db_host", "lower")
+	rc.NormalizeKeysUpper()
+
+	if got, want := rc.Entries(), []string{"DB_HOST=lower"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want the last sorted original to win %v", got, want)
+	}
+}
+
+func TestNormalizeKeysFrozenIgnore(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"debug"}, []string{"debug"})
+	rc.FreezeIgnoreKeys()
+	rc.NormalizeKeysUpper()
+
+	if got := rc.IgnoreKeys(); !reflect.DeepEqual(got, []string{"DEBUG"}) {
+		t.Errorf("IgnoreKeys() = %v, want frozen ignore keys renamed with the data", got)
+	}
+	if !rc.Has("DEBUG") {
+		t.Error("Has(DEBUG) = false, want data keys renamed")
+	}
+	if !rc.ValuesLoaded() {
+		t.Error("ValuesLoaded() = false, want DEBUG still ignored")
+	}
+}
+
+func TestSetKeyNormalizer(t *testing.T) {
+	normalize := func(key string) string {
+		return strings.ReplaceAll(strings.ToUpper(key), "-", "_")
+	}
+	rc := NewRuntimeConfig([]string{"db-host", "port"}, nil)
+	rc.Set("db-host", "localhost")
+	rc.SetKeyNormalizer(normalize)
+
+	if want := []string{"DB_HOST=localhost", "PORT="}; !reflect.DeepEqual(rc.Entries(), want) {
+		t.Errorf("Entries() = %v, want %v after re-keying", rc.Entries(), want)
+	}
+	for _, key := range []string{"db-host", "DB_HOST", "Db-Host", "db_host"} {
+		if got := rc.Get(key); got != "localhost" {
+			t.Errorf("Get(%s) = %q, want %q", key, got, "localhost")
+		}
+	}
+
+	rc.SetDefault("cache-ttl", "30s")
+	if got := rc.Get("CACHE_TTL"); got != "30s" {
+		t.Errorf("Get(CACHE_TTL) = %q after SetDefault(cache-ttl), want %q", got, "30s")
+	}
+	rc.Set("cache-ttl", "1m")
+	if value, source := rc.GetWithSource("Cache-TTL"); value != "1m" || source != SourceSet {
+		t.Errorf("GetWithSource(Cache-TTL) = %q, %v, want %q, %v", value, source, "1m", SourceSet)
+	}
+	if !rc.ResetToDefault("cache-ttl") || rc.Get("cache_ttl") != "30s" {
+		t.Errorf("ResetToDefault(cache-ttl) left %q, want the default", rc.Get("cache_ttl"))
+	}
+
+	if err := rc.SetOnce("db-host", "other"); err == nil {
+		t.Error("SetOnce(db-host) returned no error for the set DB_HOST")
+	}
+	rc.Append("hosts", "a")
+	rc.Append("HOSTS", "b")
+	if got := rc.Get("hosts"); got != "a,b" {
+		t.Errorf("Get(hosts) = %q, want %q", got, "a,b")
+	}
+	if got, err := rc.Increment("retry-count", 2); err != nil || got != 2 {
+		t.Errorf("Increment(retry-count) = %d, %v, want 2", got, err)
+	}
+
+	rc.MarkSensitive("db-host")
+	rc.AddIgnoreKey("port")
+	if !rc.IsIgnored("PORT") {
+		t.Error("IsIgnored(PORT) = false after AddIgnoreKey(port)")
+	}
+	rc.SetRequired("api-key")
+	if err := rc.ValidateAll(); err == nil || !strings.Contains(err.Error(), "API_KEY") {
+		t.Errorf("ValidateAll() error = %v, want it to name API_KEY", err)
+	}
+
+	want := []string{"API_KEY=", "CACHE_TTL=30s", "DB_HOST=********", "HOSTS=a,b", "PORT=", "RETRY_COUNT=2"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+
+	rc.SetKeyDelimiter("_")
+	if value, ok := rc.GetPath("/db/host"); !ok || value != "localhost" {
+		t.Errorf("GetPath(/db/host) = %q, %v, want %q, true", value, ok, "localhost")
+	}
+}
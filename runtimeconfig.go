@@ -4,26 +4,103 @@ package runtimeconfig
 
 import (
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 // RuntimeConfig a struct for managing environment variables
 type RuntimeConfig struct {
-	data       map[string]string // where our data is stored
-	ignoreKeys map[string]bool   // mainly used for validation step
-	mu         sync.RWMutex      // mutex for thread safe
+	data         map[string]string                     // where our data is stored
+	ignoreKeys   map[string]bool                       // mainly used for validation step
+	exclusive    [][]string                            // groups of keys that may not be set together
+	required     map[string]bool                       // keys that must be non-empty to validate
+	requiredIf   map[string][]condition                // keys required only while a condition holds
+	defaults     map[string]string                     // fallback values used when env is empty
+	sensitive    map[string]bool                       // keys whose values are masked on output
+	sensitiveRes []*regexp.Regexp                      // key patterns whose values are masked on output
+	allowed      map[string][]string                   // permitted values per key
+	patterns     map[string]*regexp.Regexp             // regexps values must match
+	lengths      map[string]lengthRange                // rune length bounds of values
+	validators   map[string][]func(value string) error // custom per key checks
+	types        map[string]ConfigType                 // declared value types
+	aliases      map[string][]string                   // alternate env var names per key
+	sources      map[string]Source                     // where each non-empty value came from
+	expires      map[string]time.Time                  // expiry of values stored with SetWithTTL
+	ttls         map[string]time.Duration              // lifetime of values stored with SetWithTTL
+	derived      map[string]deriveFunc                 // keys computed by Get from other keys
+	keyDelim     string                                // separator of nested key segments
+	normalizer   func(key string) string               // maps caller keys to canonical keys
+	maxKeys      int                                   // limit on the number of keys, 0 is unbounded
+	frozenIgnore bool                                  // blocks changes to ignoreKeys once set
+	strict       bool                                  // Set validates values before storing
+	blankIsEmpty bool                                  // whitespace only values count as empty
+	envDisabled  bool                                  // env loading methods become no-ops
+	lazyEnv      bool                                  // Get loads empty keys from env on first read
+	lazyDone     map[string]bool                       // keys already loaded lazily
+	shared       bool                                  // data, ignoreKeys and the per key state are shared with a COW copy
+	subscribers  []subscriber                          // callbacks notified of value changes
+	nextSubID    int                                   // id handed to the next subscriber
+	streams      map[<-chan ChangeEvent]func()         // stop functions of Events channels
+	lookupEnv    func(key string) (string, bool)       // env source, os.LookupEnv by default
+	missHandler  func(key string) (string, bool)       // fallback consulted by Get for empty keys
+	missCache    map[string]missEntry                  // values supplied by missHandler
+	noMissCache  bool                                  // missHandler values are not cached
+	now          func() time.Time                      // clock, time.Now by default
+	initKeys     []string                              // defaultKeys passed to the constructor
+	initIgnore   []string                              // ignoreKeys passed to the constructor
+	mu           sync.RWMutex                          // mutex for thread safe
+	accessed     map[string]bool                       // keys read through Get, guarded by accessMu
+	accessMu     sync.Mutex                            // lets Get record reads under the read lock
 }
 
 // mKeyDefaultValue package const for empty string
 const mKeyDefaultValue string = ""
 
+// mSensitiveMask package const printed in place of sensitive values
+const mSensitiveMask string = "********"
+
+// mKeyDelimiter package const for the default nested key separator
+const mKeyDelimiter string = "."
+
+// mCtxCheckInterval package const for how many keys are loaded between
+// context checks
+const mCtxCheckInterval int = 64
+
 // NewRuntimeConfig returns a RuntimeConfig initialized with defaultKeys
 // and ignoreKeys
 func NewRuntimeConfig(defaultKeys, ignoreKeys []string) *RuntimeConfig {
 	cm := &RuntimeConfig{
 		data:       make(map[string]string),
 		ignoreKeys: make(map[string]bool),
+		required:   make(map[string]bool),
+		requiredIf: make(map[string][]condition),
+		defaults:   make(map[string]string),
+		sensitive:  make(map[string]bool),
+		allowed:    make(map[string][]string),
+		patterns:   make(map[string]*regexp.Regexp),
+		lengths:    make(map[string]lengthRange),
+		validators: make(map[string][]func(value string) error),
+		types:      make(map[string]ConfigType),
+		aliases:    make(map[string][]string),
+		sources:    make(map[string]Source),
+		expires:    make(map[string]time.Time),
+		ttls:       make(map[string]time.Duration),
+		derived:    make(map[string]deriveFunc),
+		lazyDone:   make(map[string]bool),
+		keyDelim:   mKeyDelimiter,
+		lookupEnv:  os.LookupEnv,
+		missCache:  make(map[string]missEntry),
+		now:        time.Now,
+		initKeys:   slices.Clone(defaultKeys),
+		initIgnore: slices.Clone(ignoreKeys),
 	}
 	for _, key := range defaultKeys {
 		cm.data[key] = mKeyDefaultValue
@@ -49,10 +126,216 @@ func (rconfig *RuntimeConfig) CreateCopy() *RuntimeConfig {
 		newIgnoreKeys[key] = value
 	}
 
+	cp := rconfig.copyWith(newData, newIgnoreKeys)
+	cp.ownKeyState()
+	return cp
+}
+
+// Subset returns a copy of RuntimeConfig holding only the requested keys
+// that exist, along with their ignore flags
+func (rconfig *RuntimeConfig) Subset(keys ...string) *RuntimeConfig {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	newData := make(map[string]string, len(keys))
+	newIgnoreKeys := make(map[string]bool)
+	for _, key := range keys {
+		key = rconfig.canonical(key)
+		value, ok := rconfig.data[key]
+		if !ok {
+			continue
+		}
+		newData[key] = value
+		if rconfig.ignoreKeys[key] {
+			newIgnoreKeys[key] = true
+		}
+	}
+	cp := rconfig.copyWith(newData, newIgnoreKeys)
+	cp.ownKeyState()
+	return cp
+}
+
+// CreateCOWCopy returns a copy of RuntimeConfig that shares the data and
+// ignoreKeys maps with the original until either side mutates them
+// note: suited to snapshots that are mostly read
+func (rconfig *RuntimeConfig) CreateCOWCopy() *RuntimeConfig {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.shared = true
+	cp := rconfig.copyWith(rconfig.data, rconfig.ignoreKeys)
+	cp.shared = true
+	return cp
+}
+
+// copyWith returns a RuntimeConfig holding data and ignoreKeys along with
+// a copy of the remaining state, the per key sources, expiries and TTLs
+// are shared so callers must mark the copy shared or call ownKeyState,
+// callers must hold the lock
+func (rconfig *RuntimeConfig) copyWith(data map[string]string, ignoreKeys map[string]bool) *RuntimeConfig {
+	newExclusive := make([][]string, 0, len(rconfig.exclusive))
+	for _, group := range rconfig.exclusive {
+		newExclusive = append(newExclusive, append([]string(nil), group...))
+	}
+
 	return &RuntimeConfig{
-		data:       newData,
-		ignoreKeys: newIgnoreKeys,
+		data:         data,
+		ignoreKeys:   ignoreKeys,
+		exclusive:    newExclusive,
+		required:     maps.Clone(rconfig.required),
+		requiredIf:   cloneSliceMap(rconfig.requiredIf),
+		defaults:     maps.Clone(rconfig.defaults),
+		sensitive:    maps.Clone(rconfig.sensitive),
+		sensitiveRes: slices.Clone(rconfig.sensitiveRes),
+		allowed:      cloneSliceMap(rconfig.allowed),
+		patterns:     maps.Clone(rconfig.patterns),
+		lengths:      maps.Clone(rconfig.lengths),
+		validators:   cloneSliceMap(rconfig.validators),
+		types:        maps.Clone(rconfig.types),
+		aliases:      cloneSliceMap(rconfig.aliases),
+		sources:      rconfig.sources,
+		expires:      rconfig.expires,
+		ttls:         rconfig.ttls,
+		derived:      maps.Clone(rconfig.derived),
+		keyDelim:     rconfig.keyDelim,
+		normalizer:   rconfig.normalizer,
+		maxKeys:      rconfig.maxKeys,
+		frozenIgnore: rconfig.frozenIgnore,
+		strict:       rconfig.strict,
+		blankIsEmpty: rconfig.blankIsEmpty,
+		envDisabled:  rconfig.envDisabled,
+		lazyEnv:      rconfig.lazyEnv,
+		lazyDone:     maps.Clone(rconfig.lazyDone),
+		lookupEnv:    rconfig.lookupEnv,
+		missHandler:  rconfig.missHandler,
+		missCache:    maps.Clone(rconfig.missCache),
+		noMissCache:  rconfig.noMissCache,
+		now:          rconfig.now,
+		initKeys:     rconfig.initKeys,
+		initIgnore:   rconfig.initIgnore,
+	}
+}
+
+// Reset returns the RuntimeConfig to the state NewRuntimeConfig left it
+// in, discarding values, metadata and subscribers added since
+// note: channels returned by Events are closed
+func (rconfig *RuntimeConfig) Reset() {
+	var stops []func()
+	defer func() {
+		for _, stop := range stops {
+			stop() // takes the lock, so run once it is released
+		}
+	}()
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for _, stop := range rconfig.streams {
+		stops = append(stops, stop)
+	}
+	fresh := NewRuntimeConfig(rconfig.initKeys, rconfig.initIgnore)
+
+	rconfig.data = fresh.data
+	rconfig.ignoreKeys = fresh.ignoreKeys
+	rconfig.exclusive = fresh.exclusive
+	rconfig.required = fresh.required
+	rconfig.requiredIf = fresh.requiredIf
+	rconfig.defaults = fresh.defaults
+	rconfig.sensitive = fresh.sensitive
+	rconfig.sensitiveRes = fresh.sensitiveRes
+	rconfig.allowed = fresh.allowed
+	rconfig.patterns = fresh.patterns
+	rconfig.lengths = fresh.lengths
+	rconfig.validators = fresh.validators
+	rconfig.types = fresh.types
+	rconfig.aliases = fresh.aliases
+	rconfig.sources = fresh.sources
+	rconfig.expires = fresh.expires
+	rconfig.ttls = fresh.ttls
+	rconfig.derived = fresh.derived
+	rconfig.keyDelim = fresh.keyDelim
+	rconfig.normalizer = fresh.normalizer
+	rconfig.maxKeys = fresh.maxKeys
+	rconfig.frozenIgnore = fresh.frozenIgnore
+	rconfig.strict = fresh.strict
+	rconfig.blankIsEmpty = fresh.blankIsEmpty
+	rconfig.envDisabled = fresh.envDisabled
+	rconfig.lazyEnv = fresh.lazyEnv
+	rconfig.lazyDone = fresh.lazyDone
+	rconfig.shared = fresh.shared
+	rconfig.subscribers = fresh.subscribers
+	rconfig.streams = fresh.streams
+	rconfig.lookupEnv = fresh.lookupEnv
+	rconfig.missHandler = fresh.missHandler
+	rconfig.missCache = fresh.missCache
+	rconfig.noMissCache = fresh.noMissCache
+	rconfig.now = fresh.now
+	rconfig.ResetAccessTracking()
+}
+
+// unshare clones the maps still shared with a COW copy before they are
+// mutated in place, callers must hold the write lock
+func (rconfig *RuntimeConfig) unshare() {
+	if !rconfig.shared {
+		return
 	}
+	rconfig.data = maps.Clone(rconfig.data)
+	rconfig.ignoreKeys = maps.Clone(rconfig.ignoreKeys)
+	rconfig.ownKeyState()
+	rconfig.shared = false
+}
+
+// ownKeyState clones the per key sources, expiries and TTLs so they are
+// no longer shared with another RuntimeConfig
+func (rconfig *RuntimeConfig) ownKeyState() {
+	rconfig.sources = maps.Clone(rconfig.sources)
+	rconfig.expires = maps.Clone(rconfig.expires)
+	rconfig.ttls = maps.Clone(rconfig.ttls)
+}
+
+// write assigns value to key as a programmatic set and records the
+// change when the value differs, callers must hold the write lock
+func (rconfig *RuntimeConfig) write(changes *[]change, key, value string) {
+	rconfig.writeFrom(changes, key, value, SourceSet)
+}
+
+// writeFrom is write with the source of value, an empty value leaves the
+// key unset, callers must hold the write lock
+// note: a new key beyond the SetMaxKeys limit is reported and skipped
+func (rconfig *RuntimeConfig) writeFrom(changes *[]change, key, value string, source Source) {
+	if rconfig.refused(key) {
+		return
+	}
+	rconfig.unshare()
+	delete(rconfig.expires, key)
+	delete(rconfig.ttls, key)
+	delete(rconfig.missCache, key)
+	if value == "" {
+		delete(rconfig.sources, key)
+	} else {
+		rconfig.sources[key] = source
+	}
+	old := rconfig.data[key]
+	rconfig.data[key] = value
+	if old != value {
+		*changes = append(*changes, change{key: key, old: old, new: value})
+	}
+}
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// cloneSliceMap returns a copy of m whose slices do not share backing arrays
+func cloneSliceMap[V any](m map[string][]V) map[string][]V {
+	out := make(map[string][]V, len(m))
+	for key, values := range m {
+		out[key] = append([]V(nil), values...)
+	}
+	return out
 }
 
 // ClearData empties the data from a RuntimeConfig
@@ -60,34 +343,195 @@ func (rconfig *RuntimeConfig) ClearData() {
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
 	rconfig.data = make(map[string]string)
+	rconfig.sources = make(map[string]Source)
+	rconfig.expires = make(map[string]time.Time)
+	rconfig.ttls = make(map[string]time.Duration)
+	rconfig.missCache = make(map[string]missEntry)
 }
 
 // ClearIgnoreKeys empties the ignoreKeys map from a RuntimeConfig
 func (rconfig *RuntimeConfig) ClearIgnoreKeys() {
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
+	if rconfig.frozenIgnore {
+		fmt.Println("ignoreKeys are frozen, not cleared.")
+		return
+	}
 	rconfig.ignoreKeys = make(map[string]bool)
 }
 
 // Set assigns a key value pair in the RuntimeConfig data prop
+// note: with strict validation on, invalid values are reported and
+// not stored
 func (rconfig *RuntimeConfig) Set(key, value string) {
+	var changes []change
+	defer rconfig.notify(&changes)
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
-	rconfig.data[key] = value
+	key = rconfig.canonical(key)
+	if rconfig.strict && value != "" {
+		if err := rconfig.checkValue(key, value); err != nil {
+			fmt.Printf("Key '%s' not set: %v\n", key, err)
+			return
+		}
+	}
+	rconfig.write(&changes, key, value)
+}
+
+// SetOnce assigns a key value pair only if the key is absent or empty,
+// an error is returned if the key already holds a value or a new key is
+// refused by the SetMaxKeys limit
+func (rconfig *RuntimeConfig) SetOnce(key, value string) error {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if rconfig.live(key) != "" {
+		return fmt.Errorf("key '%s' is already set", key)
+	}
+	if err := rconfig.checkCapacity(key); err != nil {
+		return err
+	}
+	rconfig.write(&changes, key, value)
+	return nil
+}
+
+// Append adds value to the comma separated list held by key, the
+// separator is only added when the current value is non-empty
+func (rconfig *RuntimeConfig) Append(key, value string) {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if current := rconfig.data[key]; current != "" {
+		value = current + "," + value
+	}
+	rconfig.write(&changes, key, value)
+}
+
+// Increment adds delta to the int held by key, an empty value counting
+// as 0, and stores and returns the result, a non-numeric value or a new
+// key refused by the SetMaxKeys limit is left untouched and an error
+// returned
+func (rconfig *RuntimeConfig) Increment(key string, delta int) (int, error) {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if err := rconfig.checkCapacity(key); err != nil {
+		return 0, err
+	}
+
+	current := 0
+	if value := rconfig.data[key]; !rconfig.isEmpty(value) {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, rconfig.newParseError(key, value, "int", err)
+		}
+		current = i
+	}
+	current += delta
+	rconfig.write(&changes, key, strconv.Itoa(current))
+	return current, nil
 }
 
 // Get returns the value provided a key from RuntimeConfig data prop
+// note: an empty key falls back to the SetMissHandler handler if any,
+// derived keys are computed by their SetDerived function instead
 func (rconfig *RuntimeConfig) Get(key string) string {
 	rconfig.mu.RLock()
-	defer rconfig.mu.RUnlock()
-	return rconfig.data[key]
+	key = rconfig.canonical(key)
+	value, handler := rconfig.live(key), rconfig.missHandler
+	cached, ok := rconfig.cachedMiss(key)
+	derive := rconfig.derived[key]
+	lazy := rconfig.lazyEnv && !rconfig.envDisabled && !rconfig.lazyDone[key]
+	rconfig.mu.RUnlock()
+	rconfig.markAccessed(key)
+
+	if derive != nil {
+		return derive(rconfig)
+	}
+	if value == "" && lazy {
+		value = rconfig.loadLazy(key)
+	}
+	if value != "" || handler == nil {
+		return value
+	}
+	if ok {
+		return cached
+	}
+	return rconfig.handleMiss(key, handler)
+}
+
+// GetOrSet returns the value of key, or computes, stores and returns a
+// new one when it is empty, compute runs at most once per empty key
+// note: compute runs under the write lock and must not use the
+// RuntimeConfig, it does not run for a new key refused by the SetMaxKeys
+// limit, which is reported and yields empty
+func (rconfig *RuntimeConfig) GetOrSet(key string, compute func() string) string {
+	if value := rconfig.Get(key); value != "" {
+		return value
+	}
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if value := rconfig.live(key); value != "" {
+		return value // set by another goroutine while we waited
+	}
+	if rconfig.refused(key) {
+		return mKeyDefaultValue
+	}
+	value := compute()
+	rconfig.write(&changes, key, value)
+	return value
+}
+
+// GetFirst returns the first non-empty value among keys, checked in
+// the order given, or empty if none are set
+func (rconfig *RuntimeConfig) GetFirst(keys ...string) string {
+	for _, key := range keys {
+		if value := rconfig.Get(key); value != "" {
+			return value
+		}
+	}
+	return mKeyDefaultValue
 }
 
 // Delete removes key value pair from RuntimeConfig data prop
 func (rconfig *RuntimeConfig) Delete(key string) {
+	var changes []change
+	defer rconfig.notify(&changes)
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
+	rconfig.remove(&changes, rconfig.canonical(key))
+}
+
+// Has reports whether key is in the RuntimeConfig data prop, set or not
+func (rconfig *RuntimeConfig) Has(key string) bool {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	_, ok := rconfig.data[rconfig.canonical(key)]
+	return ok
+}
+
+// remove deletes key and records the change when it held a value,
+// callers must hold the write lock
+func (rconfig *RuntimeConfig) remove(changes *[]change, key string) {
+	rconfig.unshare()
+	if old := rconfig.data[key]; old != "" {
+		*changes = append(*changes, change{key: key, old: old})
+	}
 	delete(rconfig.data, key)
+	delete(rconfig.sources, key)
+	delete(rconfig.expires, key)
+	delete(rconfig.ttls, key)
+	delete(rconfig.missCache, key)
 }
 
 // Keys returns the keys from the RuntimeConfig data prop
@@ -101,6 +545,41 @@ func (rconfig *RuntimeConfig) Keys() []string {
 	return keys
 }
 
+// sortedDataKeys returns the keys of the data prop in ascending order
+func (rconfig *RuntimeConfig) sortedDataKeys() []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return sortedKeys(rconfig.data)
+}
+
+// EachUntil calls fn for every key value pair in no particular order,
+// stopping as soon as fn returns false
+// note: fn runs under the read lock and must not modify the
+// RuntimeConfig
+func (rconfig *RuntimeConfig) EachUntil(fn func(key, value string) bool) {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	for key, value := range rconfig.data {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+// KeysMatching returns the sorted keys matching the path.Match glob
+// pattern, e.g. DB_*, a malformed pattern matches nothing
+func (rconfig *RuntimeConfig) KeysMatching(pattern string) []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	var keys []string
+	for _, key := range sortedKeys(rconfig.data) {
+		if ok, _ := path.Match(pattern, key); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // Size the size of RuntimeConfig data prop
 func (rconfig *RuntimeConfig) Size() int {
 	rconfig.mu.RLock()
@@ -108,17 +587,77 @@ func (rconfig *RuntimeConfig) Size() int {
 	return len(rconfig.data)
 }
 
+// EffectiveSize the number of keys in the data prop that are not in
+// the ignoreKeys map
+func (rconfig *RuntimeConfig) EffectiveSize() int {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	count := 0
+	for key := range rconfig.data {
+		if !rconfig.ignoreKeys[key] {
+			count++
+		}
+	}
+	return count
+}
+
+// SetMaxKeys limits the data prop to n keys, once reached new keys are
+// refused while existing keys can still be updated, 0 removes the limit
+// note: Set and the bulk setters report refused keys, SetChecked
+// returns ErrMaxKeysReached
+func (rconfig *RuntimeConfig) SetMaxKeys(n int) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.maxKeys = n
+}
+
+// checkCapacity returns an error wrapping ErrMaxKeysReached if key is new
+// and the data prop is full, callers must hold the lock
+func (rconfig *RuntimeConfig) checkCapacity(key string) error {
+	if _, ok := rconfig.data[key]; ok || rconfig.maxKeys <= 0 || len(rconfig.data) < rconfig.maxKeys {
+		return nil
+	}
+	return fmt.Errorf("key '%s' not added: %w", key, ErrMaxKeysReached)
+}
+
+// refused reports key as not set and returns true if checkCapacity
+// refuses it, callers must hold the lock
+func (rconfig *RuntimeConfig) refused(key string) bool {
+	if rconfig.checkCapacity(key) == nil {
+		return false
+	}
+	fmt.Printf("Key '%s' not set, max of %d keys reached\n", key, rconfig.maxKeys)
+	return true
+}
+
+// ByteSize the approximate memory held by the data prop, the summed
+// byte length of every key and value
+func (rconfig *RuntimeConfig) ByteSize() int {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	size := 0
+	for key, value := range rconfig.data {
+		size += len(key) + len(value)
+	}
+	return size
+}
+
 // AddIgnoreKeys appends multiple keys to the RuntimeConfig ignoreKeys map
 func (rconfig *RuntimeConfig) AddIgnoreKeys(keys ...string) {
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
+	if rconfig.frozenIgnore {
+		fmt.Println("ignoreKeys are frozen, keys not added.")
+		return
+	}
 	for _, key := range keys {
+		key = rconfig.canonical(key)
 		if rconfig.ignoreKeys[key] {
 			fmt.Printf("Key '%s' is already in ignoreKeys.\n", key)
 			continue
 		}
 
-		rconfig.ignoreKeys[key] = true
+		rconfig.setIgnore(key, true)
 		fmt.Printf("Key '%s' added to ignoreKeys.\n", key)
 	}
 }
@@ -128,12 +667,18 @@ func (rconfig *RuntimeConfig) AddIgnoreKey(key string) {
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
 
+	if rconfig.frozenIgnore {
+		fmt.Printf("ignoreKeys are frozen, key '%s' not added.\n", key)
+		return
+	}
+	key = rconfig.canonical(key)
+
 	if rconfig.ignoreKeys[key] {
 		fmt.Printf("Key '%s' is already in ignoreKeys.\n", key)
 		return
 	}
 
-	rconfig.ignoreKeys[key] = true
+	rconfig.setIgnore(key, true)
 	fmt.Printf("Key '%s' added to ignoreKeys.\n", key)
 }
 
@@ -142,36 +687,125 @@ func (rconfig *RuntimeConfig) RemoveIgnoreKey(key string) {
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
 
+	if rconfig.frozenIgnore {
+		fmt.Printf("ignoreKeys are frozen, key '%s' not removed.\n", key)
+		return
+	}
+	key = rconfig.canonical(key)
+
 	if !rconfig.ignoreKeys[key] {
 		fmt.Printf("Key '%s' is not in ignoreKeys.\n", key)
 		return
 	}
 
-	delete(rconfig.ignoreKeys, key)
+	rconfig.setIgnore(key, false)
 	fmt.Printf("Key '%s' removed from ignoreKeys.\n", key)
 }
 
+// SetIgnore quietly sets or clears the ignore flag of a single key
+// note: does nothing once the ignoreKeys are frozen
+func (rconfig *RuntimeConfig) SetIgnore(key string, ignored bool) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if rconfig.frozenIgnore {
+		return
+	}
+	rconfig.setIgnore(rconfig.canonical(key), ignored)
+}
+
+// SetIgnoreKeys quietly replaces the whole ignoreKeys map with keys
+// note: does nothing once the ignoreKeys are frozen
+func (rconfig *RuntimeConfig) SetIgnoreKeys(keys []string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if rconfig.frozenIgnore {
+		return
+	}
+	ignoreKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		ignoreKeys[rconfig.canonical(key)] = true
+	}
+	rconfig.ignoreKeys = ignoreKeys
+}
+
+// setIgnore sets or clears the ignore flag of key, callers must hold
+// the write lock
+func (rconfig *RuntimeConfig) setIgnore(key string, ignored bool) {
+	rconfig.unshare()
+	if ignored {
+		rconfig.ignoreKeys[key] = true
+	} else {
+		delete(rconfig.ignoreKeys, key)
+	}
+}
+
+// IsIgnored reports whether key is in the RuntimeConfig ignoreKeys map
+func (rconfig *RuntimeConfig) IsIgnored(key string) bool {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return rconfig.ignoreKeys[rconfig.canonical(key)]
+}
+
+// FreezeIgnoreKeys prevents any further change to the ignoreKeys map,
+// data in the RuntimeConfig stays mutable
+// note: ignore keys are still renamed by the key normalizing methods so
+// the same keys stay ignored
+func (rconfig *RuntimeConfig) FreezeIgnoreKeys() {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.frozenIgnore = true
+}
+
 // IgnoreKeys returns a list of ignoreKeys RuntimeConfig
 func (rconfig *RuntimeConfig) IgnoreKeys() []string {
 	rconfig.mu.RLock()
 	defer rconfig.mu.RUnlock()
-	keys := make([]string, 0, len(rconfig.data))
-	for key := range rconfig.data {
+	keys := make([]string, 0, len(rconfig.ignoreKeys))
+	for key := range rconfig.ignoreKeys {
 		keys = append(keys, key)
 	}
 	return keys
 }
 
 // LoadValueFromEnv iterates over each key in the data prop
-// and calls an os.Getenv to get the value
+// and calls os.LookupEnv (or the SetEnvFunc override) to get the value
+// note: keys with a registered default fall back to it when unset,
+// does nothing after DisableEnvLoading
 func (rconfig *RuntimeConfig) LoadValueFromEnv() {
+	var changes []change
+	defer rconfig.notify(&changes)
 	rconfig.mu.Lock()
 	defer rconfig.mu.Unlock()
+	if rconfig.envDisabled {
+		return
+	}
 	for key := range rconfig.data {
-		rconfig.data[key] = os.Getenv(key)
+		value, source := rconfig.envSource(key)
+		rconfig.writeFrom(&changes, key, value, source)
 	}
 }
 
+// envValue returns the env value for key, then its aliases in order,
+// then its registered default, callers must hold the lock
+func (rconfig *RuntimeConfig) envValue(key string) string {
+	value, _ := rconfig.envSource(key)
+	return value
+}
+
+// envSource is envValue along with whether the value came from the env
+// or the default, callers must hold the lock
+func (rconfig *RuntimeConfig) envSource(key string) (string, Source) {
+	if value, _ := rconfig.lookupEnv(key); value != "" {
+		return value, SourceEnv
+	}
+	for _, alias := range rconfig.aliases[key] {
+		if value, _ := rconfig.lookupEnv(alias); value != "" {
+			return value, SourceEnv
+		}
+	}
+	return rconfig.defaults[key], SourceDefault
+}
+
 // ValuesLoaded returns a bool based on all values being populated
 // note: items in the ignoreKeys will not count against the overall
 // loaded status
@@ -179,10 +813,10 @@ func (rconfig *RuntimeConfig) ValuesLoaded() bool {
 	rconfig.mu.RLock()
 	defer rconfig.mu.RUnlock()
 	for key, value := range rconfig.data {
-		if rconfig.ignoreKeys[key] {
+		if rconfig.exempt(key) {
 			continue // skip current item if ignore
 		}
-		if value == "" {
+		if rconfig.isEmpty(value) {
 			return false // if any item empty return false
 		}
 	}
@@ -195,25 +829,70 @@ func (rconfig *RuntimeConfig) PrintMissingValues() {
 	rconfig.mu.RLock()
 	defer rconfig.mu.RUnlock()
 	for key, value := range rconfig.data {
-		if rconfig.ignoreKeys[key] {
+		if rconfig.exempt(key) {
 			continue
 		}
-		if value == "" {
+		if rconfig.isEmpty(value) {
 			fmt.Printf("%s: (not set)\n", key)
 		}
 	}
 }
 
 // PrintStatus prints a lists of what values are missing (unset)
-// note: this does not take into account ignore list
+// note: this does not take into account ignore list, sensitive
+// values are masked
 func (rconfig *RuntimeConfig) PrintStatus() {
+	rconfig.FprintStatus(os.Stdout)
+}
+
+// FprintStatus writes the PrintStatus output to w
+func (rconfig *RuntimeConfig) FprintStatus(w io.Writer) {
 	rconfig.mu.RLock()
 	defer rconfig.mu.RUnlock()
 	for key, value := range rconfig.data {
 		if value == "" {
-			fmt.Printf("%s: (not set)\n", key)
+			fmt.Fprintf(w, "%s: (not set)\n", key)
 		} else {
-			fmt.Printf("%s: %s\n", key, value)
+			fmt.Fprintf(w, "%s: %s\n", key, rconfig.displayValue(key))
 		}
 	}
 }
+
+// SetTreatBlankAsEmpty toggles treating whitespace only values as empty
+// in the loaded and missing checks, validation and the typed getters
+func (rconfig *RuntimeConfig) SetTreatBlankAsEmpty(on bool) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.blankIsEmpty = on
+}
+
+// isEmpty reports whether value counts as unset, callers must hold the
+// lock
+func (rconfig *RuntimeConfig) isEmpty(value string) bool {
+	if rconfig.blankIsEmpty {
+		return strings.TrimSpace(value) == ""
+	}
+	return value == ""
+}
+
+// typedValue returns the value of key for the typed getters, blank
+// values are returned empty when SetTreatBlankAsEmpty is on
+func (rconfig *RuntimeConfig) typedValue(key string) string {
+	value := rconfig.Get(key)
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	if rconfig.isEmpty(value) {
+		return mKeyDefaultValue
+	}
+	return value
+}
+
+// displayValue returns the value of key for output, masking it when the
+// key is sensitive and the value non-empty, callers must hold the lock
+func (rconfig *RuntimeConfig) displayValue(key string) string {
+	value := rconfig.data[key]
+	if value != "" && rconfig.isSensitive(key) {
+		return mSensitiveMask
+	}
+	return value
+}
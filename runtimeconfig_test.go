@@ -0,0 +1,502 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// envFunc returns a SetEnvFunc lookup backed by vars
+func envFunc(vars map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func TestLoadValueFromEnvFallsBackToDefault(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	rc.SetEnvFunc(envFunc(map[string]string{"HOST": "example.com"}))
+	rc.SetDefault("PORT", "8080")
+	rc.LoadValueFromEnv()
+
+	if got := rc.Get("HOST"); got != "example.com" {
+		t.Errorf("Get(HOST) = %q, want %q", got, "example.com")
+	}
+	if got := rc.Get("PORT"); got != "8080" {
+		t.Errorf("Get(PORT) = %q, want the default %q", got, "8080")
+	}
+}
+
+func TestFprintStatusMasksSensitive(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"USER", "DB_PASSWORD", "EMPTY"}, nil)
+	rc.MarkSensitive("DB_PASSWORD")
+	rc.Set("USER", "admin")
+	rc.Set("DB_PASSWORD", "hunter2")
+
+	var buf bytes.Buffer
+	rc.FprintStatus(&buf)
+	out := buf.String()
+	for _, line := range []string{"USER: admin\n", "DB_PASSWORD: ********\n", "EMPTY: (not set)\n"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("FprintStatus() = %q, want line %q", out, line)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("FprintStatus() = %q, leaks the sensitive value", out)
+	}
+}
+
+func TestIgnoreKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "DEBUG"}, []string{"DEBUG"})
+	if got := rc.IgnoreKeys(); len(got) != 1 || got[0] != "DEBUG" {
+		t.Errorf("IgnoreKeys() = %v, want [DEBUG]", got)
+	}
+}
+
+// run with -race to catch unguarded access
+func TestConcurrentAccess(t *testing.T) {
+	keys := []string{"A", "B", "C", "D"}
+	rc := NewRuntimeConfig(keys, []string{"D"})
+	rc.SetEnvFunc(envFunc(map[string]string{"A": "env-a", "C": "env-c"}))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := keys[(g+i)%len(keys)]
+				switch i % 6 {
+				case 0:
+					rc.Set(key, strconv.Itoa(i))
+				case 1:
+					rc.Get(key)
+				case 2:
+					rc.Delete(key)
+				case 3:
+					rc.LoadValueFromEnv()
+				case 4:
+					rc.CreateCopy().Set(key, "copy")
+				case 5:
+					rc.Keys()
+					rc.IgnoreKeys()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestGetFirst(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PRIMARY", "SECONDARY", "TERTIARY"}, nil)
+	if got := rc.GetFirst("PRIMARY", "SECONDARY", "TERTIARY"); got != "" {
+		t.Errorf("GetFirst() with all empty = %q, want empty", got)
+	}
+
+	rc.Set("SECONDARY", "two")
+	rc.Set("TERTIARY", "three")
+	if got := rc.GetFirst("PRIMARY", "SECONDARY", "TERTIARY"); got != "two" {
+		t.Errorf("GetFirst() = %q, want the first set later key %q", got, "two")
+	}
+}
+
+func TestFreezeIgnoreKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "DEBUG"}, []string{"DEBUG"})
+	rc.FreezeIgnoreKeys()
+
+	rc.AddIgnoreKey("HOST")
+	rc.AddIgnoreKeys("HOST", "OTHER")
+	rc.RemoveIgnoreKey("DEBUG")
+	rc.SetIgnore("HOST", true)
+	rc.SetIgnoreKeys([]string{"HOST"})
+	rc.ClearIgnoreKeys()
+	if got := rc.IgnoreKeys(); len(got) != 1 || got[0] != "DEBUG" {
+		t.Errorf("IgnoreKeys() after frozen mutations = %v, want [DEBUG]", got)
+	}
+
+	rc.Set("HOST", "example.com")
+	if got := rc.Get("HOST"); got != "example.com" {
+		t.Errorf("Get(HOST) = %q, want data to stay mutable", got)
+	}
+}
+
+func TestCreateCOWCopyIsolation(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, []string{"PORT"})
+	rc.Set("HOST", "original")
+	cp := rc.CreateCOWCopy()
+
+	rc.Set("HOST", "changed")
+	rc.AddIgnoreKey("HOST")
+	if got := cp.Get("HOST"); got != "original" {
+		t.Errorf("copy Get(HOST) = %q after writing the original, want %q", got, "original")
+	}
+	if cp.IsIgnored("HOST") {
+		t.Error("copy IsIgnored(HOST) = true after changing the original's ignore keys")
+	}
+
+	cp.Set("PORT", "8080")
+	cp.Delete("HOST")
+	cp.RemoveIgnoreKey("PORT")
+	if got := rc.Get("PORT"); got != "" {
+		t.Errorf("original Get(PORT) = %q after writing the copy, want empty", got)
+	}
+	if !rc.Has("HOST") || !rc.IsIgnored("PORT") {
+		t.Error("original lost HOST or the PORT ignore flag after changing the copy")
+	}
+}
+
+func TestCopiesKeepSourcesApart(t *testing.T) {
+	for name, copyFn := range map[string]func(rc *RuntimeConfig) *RuntimeConfig{
+		"CreateCopy":    (*RuntimeConfig).CreateCopy,
+		"CreateCOWCopy": (*RuntimeConfig).CreateCOWCopy,
+		"Subset":        func(rc *RuntimeConfig) *RuntimeConfig { return rc.Subset("HOST", "TOKEN") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"HOST", "TOKEN"}, nil)
+			rc.SetDefault("HOST", "localhost")
+			rc.SetWithTTL("TOKEN", "t", time.Hour)
+			cp := copyFn(rc)
+
+			cp.Set("HOST", "example.com")
+			cp.Set("TOKEN", "other")
+			if _, source := rc.GetWithSource("HOST"); source != SourceDefault {
+				t.Errorf("original GetWithSource(HOST) source = %v after writing the copy, want %v", source, SourceDefault)
+			}
+			rc.mu.RLock()
+			_, ok := rc.expires["TOKEN"]
+			rc.mu.RUnlock()
+			if !ok {
+				t.Error("original lost the TOKEN expiry after writing the copy")
+			}
+		})
+	}
+}
+
+func benchmarkCopy(b *testing.B, copyFn func(rc *RuntimeConfig) *RuntimeConfig) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "KEY_" + strconv.Itoa(i)
+	}
+	rc := NewRuntimeConfig(keys, nil)
+	for _, key := range keys {
+		rc.Set(key, "value")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copyFn(rc).Get("KEY_0")
+	}
+}
+
+func BenchmarkCreateCopy(b *testing.B) {
+	benchmarkCopy(b, (*RuntimeConfig).CreateCopy)
+}
+
+func BenchmarkCreateCOWCopy(b *testing.B) {
+	benchmarkCopy(b, (*RuntimeConfig).CreateCOWCopy)
+}
+
+func TestSetOnce(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"ID"}, nil)
+	if err := rc.SetOnce("ID", "first"); err != nil {
+		t.Fatalf("first SetOnce() error = %v", err)
+	}
+	if err := rc.SetOnce("ID", "second"); err == nil {
+		t.Error("second SetOnce() returned no error")
+	}
+	if got := rc.Get("ID"); got != "first" {
+		t.Errorf("Get(ID) = %q, want %q", got, "first")
+	}
+}
+
+func TestSetIgnore(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"DEBUG"}, nil)
+	rc.SetIgnore("DEBUG", true)
+	if !rc.IsIgnored("DEBUG") {
+		t.Error("IsIgnored(DEBUG) = false after SetIgnore(true)")
+	}
+	rc.SetIgnore("DEBUG", false)
+	if rc.IsIgnored("DEBUG") {
+		t.Error("IsIgnored(DEBUG) = true after SetIgnore(false)")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOSTS"}, nil)
+	rc.Append("HOSTS", "a")
+	if got := rc.Get("HOSTS"); got != "a" {
+		t.Errorf("Get(HOSTS) = %q after appending to an empty key, want %q", got, "a")
+	}
+	rc.Append("HOSTS", "b")
+	rc.Append("HOSTS", "c")
+	if got := rc.Get("HOSTS"); got != "a,b,c" {
+		t.Errorf("Get(HOSTS) = %q, want %q", got, "a,b,c")
+	}
+}
+
+func TestKeysMatching(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"DB_HOST", "DB_PORT", "REDIS_HOST", "NAME"}, nil)
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"DB_*", []string{"DB_HOST", "DB_PORT"}},
+		{"*_HOST", []string{"DB_HOST", "REDIS_HOST"}},
+		{"KAFKA_*", nil},
+	}
+	for _, tt := range tests {
+		if got := rc.KeysMatching(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("KeysMatching(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	keys, ignore := []string{"HOST", "PORT"}, []string{"PORT"}
+	rc := NewRuntimeConfig(keys, ignore)
+	called := false
+	rc.Subscribe(func(key, old, new string) { called = true })
+	rc.Set("HOST", "example.com")
+	rc.Set("EXTRA", "x")
+	rc.AddIgnoreKey("HOST")
+	rc.SetRequired("HOST")
+	rc.SetDefault("PORT", "8080")
+	rc.MarkSensitive("HOST")
+	rc.SetType("PORT", TypeInt)
+	rc.Reset()
+
+	fresh := NewRuntimeConfig(keys, ignore)
+	if got, want := rc.Entries(), fresh.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+	if got, want := rc.IgnoreKeys(), fresh.IgnoreKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("IgnoreKeys() = %v, want %v", got, want)
+	}
+	if got, want := rc.DescribeSchema(), fresh.DescribeSchema(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DescribeSchema() = %+v, want %+v", got, want)
+	}
+
+	called = false
+	rc.Set("HOST", "again")
+	if called {
+		t.Error("subscriber called after Reset")
+	}
+}
+
+func TestGetOrSetComputesOnce(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"TOKEN"}, nil)
+	var calls atomic.Int32
+
+	var wg sync.WaitGroup
+	results := make([]string, 32)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = rc.GetOrSet("TOKEN", func() string {
+				calls.Add(1)
+				return "computed"
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("compute ran %d times, want 1", n)
+	}
+	for i, got := range results {
+		if got != "computed" {
+			t.Errorf("GetOrSet() #%d = %q, want %q", i, got, "computed")
+		}
+	}
+}
+
+func TestSetTreatBlankAsEmpty(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	rc.Set("HOST", "   ")
+	if !rc.ValuesLoaded() {
+		t.Error("ValuesLoaded() = false with a blank value and the option off")
+	}
+
+	rc.SetTreatBlankAsEmpty(true)
+	if rc.ValuesLoaded() {
+		t.Error("ValuesLoaded() = true with a blank value and the option on")
+	}
+	if got := rc.MissingKeys(); !reflect.DeepEqual(got, []string{"HOST"}) {
+		t.Errorf("MissingKeys() = %v, want [HOST]", got)
+	}
+	if _, err := rc.GetInt("HOST"); err == nil {
+		t.Error("GetInt() of a blank value returned no error")
+	}
+}
+
+func TestEachUntil(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"A", "B", "C"}, nil)
+	calls := 0
+	rc.EachUntil(func(key, value string) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("EachUntil() called fn %d times, want 1 when it returns false", calls)
+	}
+
+	calls = 0
+	rc.EachUntil(func(key, value string) bool {
+		calls++
+		return true
+	})
+	if calls != 3 {
+		t.Errorf("EachUntil() called fn %d times, want 3", calls)
+	}
+}
+
+func TestEffectiveSize(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "DEBUG", "TRACE"}, []string{"DEBUG", "TRACE", "ORPHAN"})
+	if got := rc.EffectiveSize(); got != 2 {
+		t.Errorf("EffectiveSize() = %d, want 2", got)
+	}
+	if got := rc.Size(); got != 4 {
+		t.Errorf("Size() = %d, want 4", got)
+	}
+}
+
+func TestSetIgnoreKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"A", "B", "C"}, []string{"A", "B"})
+	rc.SetIgnoreKeys([]string{"C", "D"})
+
+	got := rc.IgnoreKeys()
+	slices.Sort(got)
+	if want := []string{"C", "D"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IgnoreKeys() = %v, want exactly %v", got, want)
+	}
+}
+
+func TestIncrementConcurrent(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"COUNT"}, nil)
+	var wg sync.WaitGroup
+	for g := 0; g < 20; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if _, err := rc.Increment("COUNT", 1); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := rc.Get("COUNT"); got != "1000" {
+		t.Errorf("Get(COUNT) = %q, want %q", got, "1000")
+	}
+
+	rc.Set("COUNT", "many")
+	if _, err := rc.Increment("COUNT", 1); err == nil {
+		t.Error("Increment() of a non-numeric value returned no error")
+	}
+	if got := rc.Get("COUNT"); got != "many" {
+		t.Errorf("Get(COUNT) = %q, want the non-numeric value untouched", got)
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	rc.Set("HOST", "example.com")
+	rc.Set("PORT", "80")
+	if got, want := rc.ByteSize(), len("HOST")+len("example.com")+len("PORT")+len("80"); got != want {
+		t.Errorf("ByteSize() = %d, want %d", got, want)
+	}
+}
+
+func TestSetMaxKeys(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"A"}, nil)
+	rc.SetMaxKeys(3)
+	rc.Set("B", "b")
+	rc.SetRequired("C")
+	if got := rc.Size(); got != 3 {
+		t.Fatalf("Size() = %d, want 3 keys up to the limit", got)
+	}
+
+	rc.Set("D", "d")
+	rc.SetRequired("E", "F")
+	rc.SetDefault("G", "g")
+	if err := rc.SetChecked("H", "h"); !errors.Is(err, ErrMaxKeysReached) {
+		t.Errorf("SetChecked() past the limit error = %v, want ErrMaxKeysReached", err)
+	}
+	if got := rc.Size(); got != 3 {
+		t.Errorf("Size() = %d past the limit, want 3", got)
+	}
+	if got := rc.Constraints(); len(got["E"]) != 0 {
+		t.Errorf("Constraints()[E] = %v, want refused keys left unregistered", got["E"])
+	}
+
+	rc.Set("A", "updated")
+	rc.SetRequired("A")
+	if got := rc.Get("A"); got != "updated" {
+		t.Errorf("Get(A) = %q, want existing keys still writable at the limit", got)
+	}
+}
+
+func TestSetMaxKeysRefusesWithoutSideEffects(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"A"}, nil)
+	rc.SetMaxKeys(1)
+
+	if got, err := rc.Increment("N", 5); !errors.Is(err, ErrMaxKeysReached) || got != 0 {
+		t.Errorf("Increment() past the limit = %d, %v, want 0, ErrMaxKeysReached", got, err)
+	}
+	if err := rc.SetOnce("O", "o"); !errors.Is(err, ErrMaxKeysReached) {
+		t.Errorf("SetOnce() past the limit error = %v, want ErrMaxKeysReached", err)
+	}
+
+	calls := 0
+	compute := func() string { calls++; return "v" }
+	rc.GetOrSet("G", compute)
+	rc.GetOrSet("G", compute)
+	if calls != 0 {
+		t.Errorf("GetOrSet() past the limit ran compute %d times, want 0", calls)
+	}
+
+	rc.SetWithTTL("T", "t", time.Minute)
+	rc.SetDefault("D", "d")
+	if rc.ResetToDefault("D") {
+		t.Error("ResetToDefault() past the limit = true, want false")
+	}
+
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	if got := len(rc.data); got != 1 {
+		t.Errorf("len(data) = %d, want only A", got)
+	}
+	if len(rc.expires) != 0 || len(rc.ttls) != 0 || len(rc.defaults) != 0 {
+		t.Errorf("expires %v, ttls %v, defaults %v, want nothing recorded for refused keys", rc.expires, rc.ttls, rc.defaults)
+	}
+}
+
+func TestSubset(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "DEBUG"}, []string{"DEBUG"})
+	rc.Set("HOST", "example.com")
+	rc.Set("PORT", "8080")
+
+	sub := rc.Subset("HOST", "DEBUG", "MISSING")
+	if got, want := sub.Entries(), []string{"DEBUG=", "HOST=example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Subset().Entries() = %v, want %v", got, want)
+	}
+	if !sub.IsIgnored("DEBUG") {
+		t.Error("Subset() dropped the DEBUG ignore flag")
+	}
+
+	sub.Set("HOST", "changed")
+	if got := rc.Get("HOST"); got != "example.com" {
+		t.Errorf("original Get(HOST) = %q after writing the subset, want it unchanged", got)
+	}
+}
@@ -0,0 +1,84 @@
+package runtimeconfig
+
+import "strings"
+
+// KeyDescriptor describes a single key and the constraints registered
+// against it, suitable for rendering a config form
+type KeyDescriptor struct {
+	Key       string   `json:"key"`
+	Required  bool     `json:"required"`
+	Type      string   `json:"type"`
+	Default   string   `json:"default,omitempty"`
+	Allowed   []string `json:"allowed,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Sensitive bool     `json:"sensitive"`
+	Validated bool     `json:"validated"` // a custom validator is attached
+}
+
+// DescribeSchema returns a descriptor for every key in the data prop
+// sorted by key
+// note: defaults of sensitive keys are masked
+func (rconfig *RuntimeConfig) DescribeSchema() []KeyDescriptor {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	descriptors := make([]KeyDescriptor, 0, len(rconfig.data))
+	for _, key := range sortedKeys(rconfig.data) {
+		d := KeyDescriptor{
+			Key:       key,
+			Required:  rconfig.required[key],
+			Type:      rconfig.types[key].String(),
+			Default:   rconfig.defaults[key],
+			Allowed:   append([]string(nil), rconfig.allowed[key]...),
+			Sensitive: rconfig.isSensitive(key),
+			Validated: len(rconfig.validators[key]) > 0,
+		}
+		if re, ok := rconfig.patterns[key]; ok {
+			d.Pattern = re.String()
+		}
+		if d.Sensitive && d.Default != "" {
+			d.Default = mSensitiveMask
+		}
+		descriptors = append(descriptors, d)
+	}
+	return descriptors
+}
+
+// Constraints returns, per key with at least one constraint, readable
+// descriptions such as required, required_if:STORAGE=s3, type:int,
+// enum:a,b,c, pattern:^\d+$, length:1-64 and validator for each custom check
+func (rconfig *RuntimeConfig) Constraints() map[string][]string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	out := make(map[string][]string)
+	add := func(key, constraint string) {
+		out[key] = append(out[key], constraint)
+	}
+	for key := range rconfig.required {
+		add(key, "required")
+	}
+	for key, conds := range rconfig.requiredIf {
+		for _, c := range conds {
+			add(key, "required_if:"+c.key+"="+c.value)
+		}
+	}
+	for key, t := range rconfig.types {
+		add(key, "type:"+t.String())
+	}
+	for key, values := range rconfig.allowed {
+		add(key, "enum:"+strings.Join(values, ","))
+	}
+	for key, re := range rconfig.patterns {
+		add(key, "pattern:"+re.String())
+	}
+	for key, r := range rconfig.lengths {
+		add(key, "length:"+r.String())
+	}
+	for key, fns := range rconfig.validators {
+		for range fns {
+			add(key, "validator")
+		}
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDescribeSchema(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "MODE", "DB_PASSWORD", "NAME"}, nil)
+	rc.SetRequired("PORT")
+	rc.SetType("PORT", TypeInt)
+	rc.SetDefault("PORT", "8080")
+	rc.SetAllowedValues("MODE", "dev", "prod")
+	if err := rc.SetPattern("NAME", `^[a-z]+$`); err != nil {
+		t.Fatal(err)
+	}
+	rc.AddValidator("NAME", func(string) error { return errors.New("never") })
+	rc.MarkSensitive("DB_PASSWORD")
+	rc.SetDefault("DB_PASSWORD", "hunter2")
+
+	want := []KeyDescriptor{
+		{Key: "DB_PASSWORD", Type: "string", Default: mSensitiveMask, Sensitive: true},
+		{Key: "MODE", Type: "string", Allowed: []string{"dev", "prod"}},
+		{Key: "NAME", Type: "string", Pattern: `^[a-z]+$`, Validated: true},
+		{Key: "PORT", Required: true, Type: "int", Default: "8080"},
+	}
+	got := rc.DescribeSchema()
+	for i := range got {
+		if len(got[i].Allowed) == 0 {
+			got[i].Allowed = nil
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DescribeSchema() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"NAME", "PORT", "S3_BUCKET"}, nil)
+	rc.SetRequired("NAME")
+	rc.SetLengthConstraint("NAME", 1, 64)
+	rc.AddValidator("NAME", func(string) error { return nil })
+	rc.AddValidator("NAME", func(string) error { return nil })
+	rc.SetType("PORT", TypeInt)
+	rc.SetRequiredIf("S3_BUCKET", "STORAGE", "s3")
+
+	got := rc.Constraints()
+	want := map[string][]string{
+		"NAME":      {"required", "length:1-64", "validator", "validator"},
+		"PORT":      {"type:int"},
+		"S3_BUCKET": {"required_if:STORAGE=s3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Constraints() = %v, want %v", got, want)
+	}
+}
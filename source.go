@@ -0,0 +1,46 @@
+package runtimeconfig
+
+import "strconv"
+
+// Source is where the value held by a key came from
+type Source int
+
+// the sources reported by GetWithSource
+const (
+	SourceUnset Source = iota
+	SourceDefault
+	SourceEnv
+	SourceSet
+)
+
+// String returns the lowercase name of the source
+func (s Source) String() string {
+	switch s {
+	case SourceUnset:
+		return "unset"
+	case SourceDefault:
+		return "default"
+	case SourceEnv:
+		return "env"
+	case SourceSet:
+		return "set"
+	}
+	return "Source(" + strconv.Itoa(int(s)) + ")"
+}
+
+// GetWithSource returns the value of key along with where it came from,
+// keys holding no value, or whose SetWithTTL value expired, report
+// SourceUnset
+// note: values written by the env loaders that fell back to a default
+// report SourceDefault
+func (rconfig *RuntimeConfig) GetWithSource(key string) (string, Source) {
+	rconfig.mu.RLock()
+	key = rconfig.canonical(key)
+	value, source := rconfig.live(key), rconfig.sources[key]
+	if value == "" {
+		source = SourceUnset // expired by SetWithTTL
+	}
+	rconfig.mu.RUnlock()
+	rconfig.markAccessed(key)
+	return value, source
+}
@@ -0,0 +1,40 @@
+package runtimeconfig
+
+import "testing"
+
+func TestGetWithSource(t *testing.T) {
+	type defaults struct {
+		FromStruct string `env:"FROM_STRUCT"`
+	}
+	rc := NewRuntimeConfig([]string{"UNSET", "FROM_SET", "FROM_DEFAULT", "FROM_ENV", "ENV_FALLBACK", "FROM_STRUCT", "RESET"}, nil)
+	rc.SetEnvFunc(envFunc(map[string]string{"FROM_ENV": "e"}))
+	rc.SetDefault("FROM_DEFAULT", "d")
+	rc.SetDefault("ENV_FALLBACK", "fallback")
+	rc.LoadValueFromEnv()
+	rc.Set("FROM_SET", "s")
+	if err := rc.SetDefaultsFromStruct(defaults{FromStruct: "st"}); err != nil {
+		t.Fatal(err)
+	}
+	rc.SetDefault("RESET", "r")
+	rc.Set("RESET", "changed")
+	rc.ResetToDefault("RESET")
+
+	tests := []struct {
+		key    string
+		value  string
+		source Source
+	}{
+		{"UNSET", "", SourceUnset},
+		{"FROM_SET", "s", SourceSet},
+		{"FROM_DEFAULT", "d", SourceDefault},
+		{"FROM_ENV", "e", SourceEnv},
+		{"ENV_FALLBACK", "fallback", SourceDefault},
+		{"FROM_STRUCT", "st", SourceDefault},
+		{"RESET", "r", SourceDefault},
+	}
+	for _, tt := range tests {
+		if value, source := rc.GetWithSource(tt.key); value != tt.value || source != tt.source {
+			t.Errorf("GetWithSource(%s) = %q, %v, want %q, %v", tt.key, value, source, tt.value, tt.source)
+		}
+	}
+}
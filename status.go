@@ -0,0 +1,117 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// ConfigStatus is a machine readable summary of how complete the
+// RuntimeConfig is, keys in the ignore set are not counted
+type ConfigStatus struct {
+	Total   int      `json:"total"`
+	Loaded  int      `json:"loaded"`
+	Missing []string `json:"missing"`
+	Ready   bool     `json:"ready"`
+}
+
+// Status returns the load status of the RuntimeConfig
+func (rconfig *RuntimeConfig) Status() ConfigStatus {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	missing := rconfig.missingKeys()
+	total := 0
+	for key := range rconfig.data {
+		if !rconfig.exempt(key) {
+			total++
+		}
+	}
+	return ConfigStatus{
+		Total:   total,
+		Loaded:  total - len(missing),
+		Missing: missing,
+		Ready:   len(missing) == 0,
+	}
+}
+
+// MissingKeys returns the sorted keys that are empty and not ignored
+func (rconfig *RuntimeConfig) MissingKeys() []string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return rconfig.missingKeys()
+}
+
+// exempt reports whether key is left out of missing value checks, as
+// ignored and derived keys are, callers must hold the lock
+func (rconfig *RuntimeConfig) exempt(key string) bool {
+	return rconfig.ignoreKeys[key] || rconfig.derived[key] != nil
+}
+
+// missingKeys returns the sorted keys that are empty and not ignored,
+// callers must hold the lock
+func (rconfig *RuntimeConfig) missingKeys() []string {
+	missing := []string{}
+	for _, key := range sortedKeys(rconfig.data) {
+		if !rconfig.exempt(key) && rconfig.isEmpty(rconfig.data[key]) {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// WriteMissingStub writes a .env template to w with a KEY= line,
+// preceded by a # set me comment, for every missing key in sorted order
+func (rconfig *RuntimeConfig) WriteMissingStub(w io.Writer) {
+	for _, key := range rconfig.MissingKeys() {
+		fmt.Fprintf(w, "# set me\n%s=\n", key)
+	}
+}
+
+// FprintTable writes an aligned KEY/VALUE table sorted by key to w,
+// sensitive values are masked
+func (rconfig *RuntimeConfig) FprintTable(w io.Writer) {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE")
+	for _, key := range sortedKeys(rconfig.data) {
+		value := rconfig.displayValue(key)
+		if rconfig.isEmpty(value) {
+			value = "(not set)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", key, value)
+	}
+	tw.Flush()
+}
+
+// AssertNoSecretsInStatus renders the PrintStatus output and returns an
+// error naming every sensitive key whose value appears in it unmasked
+// note: a guard for tests against a change that leaks secrets
+func (rconfig *RuntimeConfig) AssertNoSecretsInStatus() error {
+	var buf bytes.Buffer
+	rconfig.FprintStatus(&buf)
+	return rconfig.checkNoSecrets(buf.String())
+}
+
+// checkNoSecrets returns an error naming every sensitive key whose value
+// appears anywhere in out, so a change to the status format can not hide
+// a leak
+func (rconfig *RuntimeConfig) checkNoSecrets(out string) error {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	var leaked []string
+	for _, key := range sortedKeys(rconfig.data) {
+		value := rconfig.data[key]
+		if value != "" && value != mSensitiveMask && rconfig.isSensitive(key) && strings.Contains(out, value) {
+			leaked = append(leaked, key)
+		}
+	}
+	if len(leaked) > 0 {
+		return fmt.Errorf("status shows sensitive keys unmasked: '%s'", strings.Join(leaked, "', '"))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "USER", "DEBUG"}, []string{"DEBUG"})
+	rc.Set("HOST", "example.com")
+
+	want := ConfigStatus{Total: 3, Loaded: 1, Missing: []string{"PORT", "USER"}, Ready: false}
+	if got := rc.Status(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Status() = %+v, want %+v", got, want)
+	}
+
+	rc.Set("PORT", "8080")
+	rc.Set("USER", "admin")
+	want = ConfigStatus{Total: 3, Loaded: 3, Missing: []string{}, Ready: true}
+	if got := rc.Status(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Status() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteMissingStub(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "HOST", "USER", "DEBUG"}, []string{"DEBUG"})
+	rc.Set("USER", "admin")
+
+	var buf bytes.Buffer
+	rc.WriteMissingStub(&buf)
+	if got, want := buf.String(), "# set me\nHOST=\n# set me\nPORT=\n"; got != want {
+		t.Errorf("WriteMissingStub() = %q, want %q", got, want)
+	}
+}
+
+func TestFprintTable(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "API_TOKEN", "PORT"}, nil)
+	rc.MarkSensitive("API_TOKEN")
+	rc.Set("HOST", "example.com")
+	rc.Set("API_TOKEN", "abc")
+
+	var buf bytes.Buffer
+	rc.FprintTable(&buf)
+	want := "KEY        VALUE\n" +
+		"API_TOKEN  ********\n" +
+		"HOST       example.com\n" +
+		"PORT       (not set)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("FprintTable() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAssertNoSecretsInStatus(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "API_TOKEN", "DB_PASSWORD", "PORT"}, nil)
+	rc.MarkSensitive("API_TOKEN", "DB_PASSWORD", "PORT")
+	rc.Set("HOST", "example.com")
+	rc.Set("API_TOKEN", "t0ken")
+	rc.Set("DB_PASSWORD", "hunter2")
+
+	if err := rc.AssertNoSecretsInStatus(); err != nil {
+		t.Errorf("AssertNoSecretsInStatus() error = %v, want nil with masking", err)
+	}
+
+	// a status renderer in another format that forgot to mask
+	var buf bytes.Buffer
+	for _, key := range rc.sortedDataKeys() {
+		fmt.Fprintf(&buf, "%s=%s ", key, rc.Get(key))
+	}
+	err := rc.checkNoSecrets(buf.String())
+	if err == nil || !strings.Contains(err.Error(), "'API_TOKEN', 'DB_PASSWORD'") {
+		t.Errorf("checkNoSecrets() error = %v, want it to name API_TOKEN and DB_PASSWORD", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "HOST") {
+		t.Errorf("checkNoSecrets() error = %v, names the non-sensitive HOST", err)
+	}
+}
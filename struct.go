@@ -0,0 +1,190 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structField pairs a settable struct field with its config key
+type structField struct {
+	key   string
+	value reflect.Value
+}
+
+// structFields returns the exported fields of the struct v points to or
+// holds, keyed by their env tag or field name, fields tagged env:"-"
+// are skipped
+func structFields(v interface{}) ([]structField, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %T", v)
+	}
+
+	var fields []structField
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+		fields = append(fields, structField{key: key, value: rv.Field(i)})
+	}
+	return fields, nil
+}
+
+// formatField converts a scalar field value to its config string
+func formatField(v reflect.Value) (string, error) {
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// assignField parses value into the scalar field v
+func assignField(v reflect.Value, value string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err == nil {
+			v.SetInt(int64(d))
+		}
+		return err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Unmarshal populates the struct pointed to by v from the data prop,
+// keys come from env tags or the field name and fields whose key is
+// empty are left untouched
+// note: conversion failures are returned as *ParseError joined together,
+// sensitive values are masked in them
+func (rconfig *RuntimeConfig) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("expected a non-nil struct pointer, got %T", v)
+	}
+	fields, err := structFields(v)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range fields {
+		value := rconfig.typedValue(f.key)
+		if value == "" {
+			continue
+		}
+		if err := assignField(f.value, value); err != nil {
+			errs = append(errs, rconfig.parseError(f.key, value, f.value.Type().String(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Bind returns a T populated from rc, see Unmarshal
+func Bind[T any](rc *RuntimeConfig) (T, error) {
+	var t T
+	err := rc.Unmarshal(&t)
+	return t, err
+}
+
+// ToStruct returns a T populated from rc, it is the same as Bind
+func ToStruct[T any](rc *RuntimeConfig) (T, error) {
+	return Bind[T](rc)
+}
+
+// SetDefaultsFromStruct registers the current value of every field of
+// the struct v as the default for its key, keys come from env tags or
+// the field name
+// note: a new key beyond the SetMaxKeys limit is reported and skipped
+func (rconfig *RuntimeConfig) SetDefaultsFromStruct(v interface{}) error {
+	fields, err := structFields(v)
+	if err != nil {
+		return err
+	}
+
+	defaults := make(map[string]string, len(fields))
+	var errs []error
+	for _, f := range fields {
+		value, err := formatField(f.value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("key '%s': %w", f.key, err))
+			continue
+		}
+		defaults[f.key] = value
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for key, value := range defaults {
+		key = rconfig.canonical(key)
+		if rconfig.refused(key) {
+			continue
+		}
+		rconfig.defaults[key] = value
+		if rconfig.data[key] == "" {
+			rconfig.writeFrom(&changes, key, value, SourceDefault)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetDefaultsFromStruct(t *testing.T) {
+	type defaults struct {
+		Port  int    `env:"PORT"`
+		Debug bool   `env:"DEBUG"`
+		Name  string `env:"NAME"`
+	}
+	rc := NewRuntimeConfig([]string{"PORT", "DEBUG", "NAME"}, nil)
+	rc.Set("NAME", "custom")
+	if err := rc.SetDefaultsFromStruct(defaults{Port: 8080, Debug: true, Name: "app"}); err != nil {
+		t.Fatalf("SetDefaultsFromStruct() error = %v", err)
+	}
+
+	for key, want := range map[string]string{"PORT": "8080", "DEBUG": "true", "NAME": "custom"} {
+		if got := rc.Get(key); got != want {
+			t.Errorf("Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+	rc.Set("NAME", "")
+	if !rc.ResetToDefault("NAME") || rc.Get("NAME") != "app" {
+		t.Errorf("Get(NAME) = %q after ResetToDefault, want the struct default %q", rc.Get("NAME"), "app")
+	}
+
+	if err := rc.SetDefaultsFromStruct(42); err == nil {
+		t.Error("SetDefaultsFromStruct() with a non-struct returned no error")
+	}
+}
+
+func TestBind(t *testing.T) {
+	type config struct {
+		Host    string        `env:"HOST"`
+		Port    int           `env:"PORT"`
+		Debug   bool          `env:"DEBUG"`
+		Ratio   float64       `env:"RATIO"`
+		Timeout time.Duration `env:"TIMEOUT"`
+		Skipped string        `env:"-"`
+	}
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "DEBUG", "RATIO", "TIMEOUT"}, nil)
+	rc.Set("HOST", "example.com")
+	rc.Set("PORT", "8080")
+	rc.Set("DEBUG", "true")
+	rc.Set("RATIO", "0.25")
+	rc.Set("TIMEOUT", "3s")
+
+	got, err := Bind[config](rc)
+	if err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	want := config{Host: "example.com", Port: 8080, Debug: true, Ratio: 0.25, Timeout: 3 * time.Second}
+	if got != want {
+		t.Errorf("Bind() = %+v, want %+v", got, want)
+	}
+
+	rc.Set("PORT", "eighty")
+	_, err = Bind[config](rc)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Key != "PORT" {
+		t.Errorf("Bind() error = %v, want a PORT *ParseError", err)
+	}
+}
+
+func TestToStruct(t *testing.T) {
+	type config struct {
+		Name    string
+		Workers uint          `env:"WORKERS"`
+		Level   int8          `env:"LEVEL"`
+		Scale   float32       `env:"SCALE,omitempty"`
+		Grace   time.Duration `env:"GRACE"`
+		Unset   int           `env:"UNSET"`
+		hidden  string
+	}
+	rc := NewRuntimeConfig([]string{"Name", "WORKERS", "LEVEL", "SCALE", "GRACE", "UNSET", "hidden"}, nil)
+	rc.Set("Name", "api")
+	rc.Set("WORKERS", "4")
+	rc.Set("LEVEL", "-3")
+	rc.Set("SCALE", "1.5")
+	rc.Set("GRACE", "250ms")
+	rc.Set("hidden", "x")
+
+	got, err := ToStruct[config](rc)
+	if err != nil {
+		t.Fatalf("ToStruct() error = %v", err)
+	}
+	want := config{Name: "api", Workers: 4, Level: -3, Scale: 1.5, Grace: 250 * time.Millisecond}
+	if got != want {
+		t.Errorf("ToStruct() = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"negative uint", "WORKERS", "-1"},
+		{"int8 overflow", "LEVEL", "200"},
+		{"bad duration", "GRACE", "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := rc.CreateCopy()
+			bad.Set(tt.key, tt.value)
+			_, err := ToStruct[config](bad)
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Key != tt.key || pe.Value != tt.value {
+				t.Errorf("ToStruct() error = %v, want a %s *ParseError", err, tt.key)
+			}
+		})
+	}
+
+	if _, err := ToStruct[int](rc); err == nil {
+		t.Error("ToStruct[int]() returned no error")
+	}
+}
@@ -0,0 +1,124 @@
+package runtimeconfig
+
+import (
+	"slices"
+	"sync"
+)
+
+// change is a single value transition reported to subscribers
+type change struct {
+	key, old, new string
+}
+
+// subscriber is a registered change callback and the id used to remove it
+type subscriber struct {
+	id int
+	fn func(key, old, new string)
+}
+
+// Subscribe registers fn to be called after every value change and
+// returns a function that removes it again
+// note: fn runs without the lock held so it may use the RuntimeConfig
+func (rconfig *RuntimeConfig) Subscribe(fn func(key, old, new string)) (unsubscribe func()) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	id := rconfig.nextSubID
+	rconfig.nextSubID++
+	rconfig.subscribers = append(rconfig.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		rconfig.mu.Lock()
+		defer rconfig.mu.Unlock()
+		rconfig.subscribers = slices.DeleteFunc(rconfig.subscribers, func(s subscriber) bool {
+			return s.id == id
+		})
+	}
+}
+
+// notify passes the collected changes to every subscriber, it is
+// deferred ahead of the lock so it runs once the lock is released
+func (rconfig *RuntimeConfig) notify(changes *[]change) {
+	if len(*changes) == 0 {
+		return
+	}
+	rconfig.mu.RLock()
+	subs := slices.Clone(rconfig.subscribers)
+	rconfig.mu.RUnlock()
+
+	for _, c := range *changes {
+		for _, s := range subs {
+			s.fn(c.key, c.old, c.new)
+		}
+	}
+}
+
+// ChangeEvent describes a single value change delivered by Events
+type ChangeEvent struct {
+	Key string
+	Old string
+	New string
+}
+
+// eventStream is a buffered channel fed by a subscriber, mu keeps a
+// send from racing the close
+type eventStream struct {
+	mu     sync.Mutex
+	ch     chan ChangeEvent
+	closed bool
+}
+
+// send delivers e without blocking, dropping it when the buffer is full
+func (s *eventStream) send(e ChangeEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- e:
+	default: // buffer full, drop the event
+	}
+}
+
+// close closes the channel once
+func (s *eventStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// Events returns a channel receiving a ChangeEvent for every value
+// change, pass it to StopEvents to close it
+// note: events are dropped rather than blocking when the buffer is full
+func (rconfig *RuntimeConfig) Events(buffer int) <-chan ChangeEvent {
+	stream := &eventStream{ch: make(chan ChangeEvent, buffer)}
+	unsubscribe := rconfig.Subscribe(func(key, old, new string) {
+		stream.send(ChangeEvent{Key: key, Old: old, New: new})
+	})
+
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if rconfig.streams == nil {
+		rconfig.streams = make(map[<-chan ChangeEvent]func())
+	}
+	rconfig.streams[stream.ch] = func() {
+		unsubscribe()
+		stream.close()
+	}
+	return stream.ch
+}
+
+// StopEvents unsubscribes and closes a channel returned by Events
+func (rconfig *RuntimeConfig) StopEvents(ch <-chan ChangeEvent) {
+	rconfig.mu.Lock()
+	stop, ok := rconfig.streams[ch]
+	delete(rconfig.streams, ch)
+	rconfig.mu.Unlock()
+
+	if ok {
+		stop()
+	}
+}
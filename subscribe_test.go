@@ -0,0 +1,81 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	var calls []change
+	unsubscribe := rc.Subscribe(func(key, old, new string) {
+		calls = append(calls, change{key: key, old: old, new: new})
+	})
+
+	rc.Set("HOST", "a")
+	rc.Set("HOST", "a") // unchanged, not reported
+	if len(calls) != 1 || calls[0] != (change{key: "HOST", new: "a"}) {
+		t.Fatalf("calls = %+v, want exactly one HOST change to a", calls)
+	}
+
+	unsubscribe()
+	rc.Set("HOST", "b")
+	if len(calls) != 1 {
+		t.Errorf("calls = %+v, want no calls after unsubscribe", calls)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST"}, nil)
+	ch := rc.Events(4)
+	rc.Set("HOST", "a")
+	rc.Delete("HOST")
+
+	want := []ChangeEvent{{Key: "HOST", New: "a"}, {Key: "HOST", Old: "a"}}
+	for _, w := range want {
+		if got := <-ch; got != w {
+			t.Errorf("event = %+v, want %+v", got, w)
+		}
+	}
+
+	rc.StopEvents(ch)
+	if _, ok := <-ch; ok {
+		t.Error("channel still open after StopEvents")
+	}
+	rc.Set("HOST", "b") // must not panic sending on the closed channel
+}
+
+func TestEventsFullBuffer(t *testing.T) {
+	rc := NewRuntimeConfig(nil, nil)
+	ch := rc.Events(2)
+	for i := 0; i < 5; i++ {
+		rc.Set("KEY", strconv.Itoa(i)) // must not block once the buffer is full
+	}
+	rc.StopEvents(ch)
+
+	var got []string
+	for e := range ch {
+		got = append(got, e.New)
+	}
+	if want := []string{"0", "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want the first %v with the rest dropped", got, want)
+	}
+}
+
+func TestResetClosesEvents(t *testing.T) {
+	rc := NewRuntimeConfig(nil, nil)
+	ch := rc.Events(1)
+	rc.Reset()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("received an event, want the channel closed by Reset")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed by Reset")
+	}
+	rc.StopEvents(ch) // no-op once Reset has stopped it
+}
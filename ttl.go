@@ -0,0 +1,42 @@
+package runtimeconfig
+
+import "time"
+
+// SetClock replaces the function used for time based features such as
+// SetWithTTL, passing nil restores time.Now
+// note: useful for driving expiry with a fake clock in tests
+func (rconfig *RuntimeConfig) SetClock(now func() time.Time) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	if now == nil {
+		now = time.Now
+	}
+	rconfig.now = now
+}
+
+// SetWithTTL assigns a key value pair that Get treats as empty once ttl
+// has elapsed, so a miss handler or GetOrSet can compute it afresh, a
+// value the miss handler supplies is cached for ttl as well
+// note: any later write to key drops the expiry
+func (rconfig *RuntimeConfig) SetWithTTL(key, value string, ttl time.Duration) {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if rconfig.refused(key) {
+		return
+	}
+	rconfig.write(&changes, key, value)
+	rconfig.expires[key] = rconfig.now().Add(ttl)
+	rconfig.ttls[key] = ttl
+}
+
+// live returns the value of key, or empty once a TTL set with
+// SetWithTTL has elapsed, callers must hold the lock
+func (rconfig *RuntimeConfig) live(key string) string {
+	if expiry, ok := rconfig.expires[key]; ok && !rconfig.now().Before(expiry) {
+		return mKeyDefaultValue
+	}
+	return rconfig.data[key]
+}
@@ -0,0 +1,117 @@
+package runtimeconfig
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock for SetClock that only moves when advanced
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestSetWithTTLRefreshesThroughMissHandler(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rc := NewRuntimeConfig([]string{"TOKEN"}, nil)
+	rc.SetClock(clock.Now)
+	calls := 0
+	rc.SetMissHandler(func(key string) (string, bool) {
+		calls++
+		return "token-" + strconv.Itoa(calls), true
+	})
+	rc.SetWithTTL("TOKEN", "token-0", time.Hour)
+
+	steps := []struct {
+		advance time.Duration
+		want    string
+	}{
+		{0, "token-0"},
+		{59 * time.Minute, "token-0"},
+		{time.Minute, "token-1"},
+		{30 * time.Minute, "token-1"},
+		{30 * time.Minute, "token-2"},
+	}
+	for i, step := range steps {
+		clock.Advance(step.advance)
+		if got := rc.Get("TOKEN"); got != step.want {
+			t.Errorf("step %d: Get(TOKEN) = %q, want %q", i, got, step.want)
+		}
+	}
+
+	before := calls
+	for i := 0; i < 100; i++ {
+		clock.Advance(time.Hour)
+		rc.Get("TOKEN")
+	}
+	if got := calls - before; got != 100 {
+		t.Errorf("handler refreshed %d times over 100 TTLs, want 100", got)
+	}
+}
+
+func TestSetWithTTLLaterWriteDropsExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rc := NewRuntimeConfig(nil, nil)
+	rc.SetClock(clock.Now)
+	rc.SetWithTTL("KEY", "short", time.Second)
+	rc.Set("KEY", "kept")
+
+	clock.Advance(time.Hour)
+	if got := rc.Get("KEY"); got != "kept" {
+		t.Errorf("Get(KEY) = %q, want %q as Set dropped the expiry", got, "kept")
+	}
+}
+
+func TestSetWithTTLExpiryInGetWithSourceAndSetOnce(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rc := NewRuntimeConfig(nil, nil)
+	rc.SetClock(clock.Now)
+	rc.SetWithTTL("KEY", "short", time.Minute)
+
+	if value, source := rc.GetWithSource("KEY"); value != "short" || source != SourceSet {
+		t.Errorf("GetWithSource(KEY) = %q, %v before expiry, want %q, %v", value, source, "short", SourceSet)
+	}
+	clock.Advance(time.Minute)
+	if value, source := rc.GetWithSource("KEY"); value != "" || source != SourceUnset {
+		t.Errorf("GetWithSource(KEY) = %q, %v after expiry, want empty, %v", value, source, SourceUnset)
+	}
+	if err := rc.SetOnce("KEY", "fresh"); err != nil {
+		t.Errorf("SetOnce(KEY) after expiry error = %v", err)
+	}
+	if got := rc.Get("KEY"); got != "fresh" {
+		t.Errorf("Get(KEY) = %q, want %q", got, "fresh")
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rc := NewRuntimeConfig(nil, nil)
+	rc.SetClock(clock.Now)
+	rc.SetWithTTL("SESSION", "abc", 10*time.Minute)
+
+	clock.Advance(9 * time.Minute)
+	if got := rc.Get("SESSION"); got != "abc" {
+		t.Errorf("Get(SESSION) = %q before expiry, want %q", got, "abc")
+	}
+	clock.Advance(time.Minute)
+	if got := rc.Get("SESSION"); got != "" {
+		t.Errorf("Get(SESSION) = %q at expiry, want empty", got)
+	}
+	if got := rc.GetOrSet("SESSION", func() string { return "fresh" }); got != "fresh" {
+		t.Errorf("GetOrSet(SESSION) = %q after expiry, want %q", got, "fresh")
+	}
+
+	rc.SetClock(nil)
+	rc.SetWithTTL("SESSION", "real", time.Hour)
+	if got := rc.Get("SESSION"); got != "real" {
+		t.Errorf("Get(SESSION) = %q with the real clock restored, want %q", got, "real")
+	}
+}
@@ -0,0 +1,128 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"time"
+)
+
+// prior is the state of a key before a batch of writes
+type prior struct {
+	value  string
+	source Source
+	expiry time.Time
+	ttl    time.Duration
+	ok     bool
+}
+
+// remember records the current state of key in saved unless it was
+// already recorded, callers must hold the lock
+func (rconfig *RuntimeConfig) remember(saved map[string]prior, key string) {
+	if _, ok := saved[key]; !ok {
+		value, ok := rconfig.data[key]
+		saved[key] = prior{value: value, source: rconfig.sources[key], expiry: rconfig.expires[key], ttl: rconfig.ttls[key], ok: ok}
+	}
+}
+
+// rollback restores the keys recorded in saved, keys that did not exist
+// are removed again, callers must hold the lock
+func (rconfig *RuntimeConfig) rollback(saved map[string]prior) {
+	rconfig.unshare()
+	for key, p := range saved {
+		if p.ok {
+			rconfig.data[key] = p.value
+		} else {
+			delete(rconfig.data, key)
+		}
+		if p.value != "" {
+			rconfig.sources[key] = p.source
+		} else {
+			delete(rconfig.sources, key)
+		}
+		if !p.expiry.IsZero() {
+			rconfig.expires[key] = p.expiry
+			rconfig.ttls[key] = p.ttl
+		}
+	}
+}
+
+// UpdateTx applies updates as a single transaction, when ValidateAll
+// fails afterwards every update is rolled back and the validation error
+// returned
+// note: subscribers only hear about committed changes
+func (rconfig *RuntimeConfig) UpdateTx(updates map[string]string) error {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+
+	saved := make(map[string]prior, len(updates))
+	var pending []change
+	for key, value := range updates {
+		key = rconfig.canonical(key)
+		if err := rconfig.checkCapacity(key); err != nil {
+			rconfig.rollback(saved)
+			return err
+		}
+		rconfig.remember(saved, key)
+		rconfig.write(&pending, key, value)
+	}
+	if err := rconfig.validate(); err != nil {
+		rconfig.rollback(saved)
+		return err
+	}
+	changes = pending
+	return nil
+}
+
+// PatchOp is a single operation applied by ApplyPatch, Op is one of
+// add, replace or remove and Value is unused by remove
+type PatchOp struct {
+	Op    string `json:"op"`
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// ApplyPatch applies ops in order as a single transaction, add requires
+// a new key while replace and remove require an existing one, on the
+// first failing op everything is rolled back and its error returned
+func (rconfig *RuntimeConfig) ApplyPatch(ops []PatchOp) error {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+
+	saved := make(map[string]prior, len(ops))
+	var pending []change
+	for i, op := range ops {
+		op.Key = rconfig.canonical(op.Key)
+		_, exists := rconfig.data[op.Key]
+		var err error
+		switch op.Op {
+		case "add":
+			if exists {
+				err = fmt.Errorf("key '%s' already exists", op.Key)
+			} else {
+				err = rconfig.checkCapacity(op.Key)
+			}
+		case "replace", "remove":
+			if !exists {
+				err = fmt.Errorf("key '%s' does not exist", op.Key)
+			}
+		default:
+			err = fmt.Errorf("unknown op '%s'", op.Op)
+		}
+		if err != nil {
+			rconfig.rollback(saved)
+			return fmt.Errorf("patch op %d: %w", i, err)
+		}
+
+		rconfig.remember(saved, op.Key)
+		if op.Op == "remove" {
+			rconfig.remove(&pending, op.Key)
+			continue
+		}
+		rconfig.write(&pending, op.Key, op.Value)
+	}
+	changes = pending
+	return nil
+}
@@ -0,0 +1,72 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateTx(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT"}, nil)
+	rc.SetType("PORT", TypeInt)
+	rc.Set("HOST", "old")
+	var heard []string
+	rc.Subscribe(func(key, old, new string) { heard = append(heard, key) })
+
+	if err := rc.UpdateTx(map[string]string{"HOST": "new", "PORT": "8080"}); err != nil {
+		t.Fatalf("UpdateTx() error = %v", err)
+	}
+	if got, want := rc.Entries(), []string{"HOST=new", "PORT=8080"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() after commit = %v, want %v", got, want)
+	}
+	if len(heard) != 2 {
+		t.Errorf("subscriber heard %v, want both committed changes", heard)
+	}
+
+	heard = nil
+	if err := rc.UpdateTx(map[string]string{"HOST": "newer", "PORT": "eighty", "EXTRA": "x"}); err == nil {
+		t.Fatal("UpdateTx() with an invalid value returned no error")
+	}
+	if got, want := rc.Entries(), []string{"HOST=new", "PORT=8080"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() after rollback = %v, want %v", got, want)
+	}
+	if _, source := rc.GetWithSource("HOST"); source != SourceSet {
+		t.Errorf("GetWithSource(HOST) source = %v after rollback, want set", source)
+	}
+	if len(heard) != 0 {
+		t.Errorf("subscriber heard %v, want nothing for a rolled back transaction", heard)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"HOST", "PORT", "OLD"}, nil)
+	rc.Set("HOST", "a")
+	rc.Set("OLD", "x")
+
+	err := rc.ApplyPatch([]PatchOp{
+		{Op: "add", Key: "USER", Value: "admin"},
+		{Op: "replace", Key: "HOST", Value: "b"},
+		{Op: "remove", Key: "OLD"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	want := []string{"HOST=b", "PORT=", "USER=admin"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+
+	failing := [][]PatchOp{
+		{{Op: "replace", Key: "HOST", Value: "c"}, {Op: "add", Key: "PORT", Value: "1"}},
+		{{Op: "remove", Key: "USER"}, {Op: "replace", Key: "MISSING", Value: "1"}},
+		{{Op: "add", Key: "NEW", Value: "1"}, {Op: "remove", Key: "MISSING"}},
+		{{Op: "replace", Key: "HOST", Value: "c"}, {Op: "move", Key: "HOST"}},
+	}
+	for _, ops := range failing {
+		if err := rc.ApplyPatch(ops); err == nil {
+			t.Errorf("ApplyPatch(%v) returned no error", ops)
+		}
+		if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Entries() after failed ApplyPatch(%v) = %v, want %v", ops, got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ConfigType is the declared type of the value held by a key
+type ConfigType int
+
+// the types a key can be declared as with SetType
+const (
+	TypeString ConfigType = iota
+	TypeInt
+	TypeBool
+	TypeFloat
+	TypeDuration
+)
+
+// String returns the lowercase name of the type
+func (t ConfigType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "int"
+	case TypeBool:
+		return "bool"
+	case TypeFloat:
+		return "float"
+	case TypeDuration:
+		return "duration"
+	}
+	return "ConfigType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// parse converts value to the Go type matching t
+func (t ConfigType) parse(value string) (interface{}, error) {
+	switch t {
+	case TypeInt:
+		return strconv.Atoi(value)
+	case TypeBool:
+		return strconv.ParseBool(value)
+	case TypeFloat:
+		return strconv.ParseFloat(value, 64)
+	case TypeDuration:
+		return time.ParseDuration(value)
+	}
+	return value, nil
+}
+
+// SetType declares the type of the value held by key
+func (rconfig *RuntimeConfig) SetType(key string, t ConfigType) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.types[rconfig.canonical(key)] = t
+}
+
+// ValidateTypes parses the value of every typed key and returns the
+// failures joined into a single error
+// note: empty values are skipped, see SetRequired
+func (rconfig *RuntimeConfig) ValidateTypes() error {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	var errs []error
+	for _, key := range sortedKeys(rconfig.types) {
+		if err := rconfig.checkType(key, rconfig.data[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TypeViolations returns, for every typed key whose current value fails
+// to parse, the parse error message
+// note: empty values are skipped as in ValidateTypes
+func (rconfig *RuntimeConfig) TypeViolations() map[string]string {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	violations := make(map[string]string)
+	for key := range rconfig.types {
+		if err := rconfig.checkType(key, rconfig.data[key]); err != nil {
+			violations[key] = err.Error()
+		}
+	}
+	return violations
+}
+
+// checkType parses a non-empty value of key according to its declared
+// type, callers must hold the lock
+func (rconfig *RuntimeConfig) checkType(key, value string) error {
+	t, ok := rconfig.types[key]
+	if !ok || value == "" {
+		return nil
+	}
+	if _, err := t.parse(value); err != nil {
+		return rconfig.newParseError(key, value, t.String(), err)
+	}
+	return nil
+}
+
+// TypedMap returns every key with its value parsed according to its
+// declared type, untyped keys and values that fail to parse are
+// returned as the raw string
+func (rconfig *RuntimeConfig) TypedMap() map[string]interface{} {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+
+	out := make(map[string]interface{}, len(rconfig.data))
+	for key, value := range rconfig.data {
+		out[key] = value
+		if t, ok := rconfig.types[key]; ok {
+			if typed, err := t.parse(value); err == nil {
+				out[key] = typed
+			}
+		}
+	}
+	return out
+}
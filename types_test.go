@@ -0,0 +1,70 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypedMap(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "DEBUG", "TIMEOUT", "NAME", "BAD"}, nil)
+	rc.SetType("PORT", TypeInt)
+	rc.SetType("DEBUG", TypeBool)
+	rc.SetType("TIMEOUT", TypeDuration)
+	rc.SetType("BAD", TypeInt)
+	rc.Set("PORT", "8080")
+	rc.Set("DEBUG", "true")
+	rc.Set("TIMEOUT", "2s")
+	rc.Set("NAME", "app")
+	rc.Set("BAD", "x")
+
+	want := map[string]interface{}{
+		"PORT":    8080,
+		"DEBUG":   true,
+		"TIMEOUT": 2 * time.Second,
+		"NAME":    "app",
+		"BAD":     "x",
+	}
+	if got := rc.TypedMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TypedMap() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateTypes(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "RATIO", "EMPTY"}, nil)
+	rc.SetType("PORT", TypeInt)
+	rc.SetType("RATIO", TypeFloat)
+	rc.SetType("EMPTY", TypeInt)
+	rc.Set("PORT", "8080")
+	rc.Set("RATIO", "0.5")
+	if err := rc.ValidateTypes(); err != nil {
+		t.Errorf("ValidateTypes() error = %v, want nil", err)
+	}
+
+	rc.Set("PORT", "eighty")
+	err := rc.ValidateTypes()
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Key != "PORT" || pe.Kind != "int" {
+		t.Errorf("ValidateTypes() error = %v, want a PORT int *ParseError", err)
+	}
+}
+
+func TestTypeViolations(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "DEBUG", "TIMEOUT"}, nil)
+	rc.SetType("PORT", TypeInt)
+	rc.SetType("DEBUG", TypeBool)
+	rc.SetType("TIMEOUT", TypeDuration)
+	rc.Set("PORT", "8080")
+	rc.Set("DEBUG", "true")
+	if got := rc.TypeViolations(); len(got) != 0 {
+		t.Errorf("TypeViolations() = %v, want none", got)
+	}
+
+	rc.Set("DEBUG", "maybe")
+	rc.Set("TIMEOUT", "soon")
+	got := rc.TypeViolations()
+	if len(got) != 2 || got["DEBUG"] == "" || got["TIMEOUT"] == "" {
+		t.Errorf("TypeViolations() = %v, want DEBUG and TIMEOUT", got)
+	}
+}
@@ -0,0 +1,283 @@
+package runtimeconfig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// SetMutuallyExclusive registers a group of keys of which at most one
+// may hold a non-empty value
+func (rconfig *RuntimeConfig) SetMutuallyExclusive(keys ...string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	group := make([]string, len(keys))
+	for i, key := range keys {
+		group[i] = rconfig.canonical(key)
+	}
+	rconfig.exclusive = append(rconfig.exclusive, group)
+}
+
+// SetRequired marks keys that must hold a non-empty value to validate,
+// keys not yet in the data prop are added with an empty value
+// note: a new key beyond the SetMaxKeys limit is reported and skipped
+func (rconfig *RuntimeConfig) SetRequired(keys ...string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.unshare()
+	for _, key := range keys {
+		key = rconfig.canonical(key)
+		if rconfig.refused(key) {
+			continue
+		}
+		rconfig.required[key] = true
+		if _, ok := rconfig.data[key]; !ok {
+			rconfig.data[key] = mKeyDefaultValue
+		}
+	}
+}
+
+// condition is satisfied when key holds exactly value
+type condition struct {
+	key, value string
+}
+
+// SetRequiredIf makes key required only while whenKey holds whenValue,
+// e.g. S3_BUCKET when STORAGE=s3
+func (rconfig *RuntimeConfig) SetRequiredIf(key, whenKey, whenValue string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	rconfig.requiredIf[key] = append(rconfig.requiredIf[key], condition{key: rconfig.canonical(whenKey), value: whenValue})
+}
+
+// SetDefault registers a fallback value for key, the value is applied
+// right away when the key is currently empty
+// note: a new key beyond the SetMaxKeys limit is reported and skipped
+func (rconfig *RuntimeConfig) SetDefault(key, value string) {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if rconfig.refused(key) {
+		return
+	}
+	rconfig.defaults[key] = value
+	if rconfig.data[key] == "" {
+		rconfig.writeFrom(&changes, key, value, SourceDefault)
+	}
+}
+
+// ResetToDefault sets key back to its registered default and reports
+// whether a default existed, keys without one are set to empty
+func (rconfig *RuntimeConfig) ResetToDefault(key string) bool {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if rconfig.refused(key) {
+		return false
+	}
+	value, ok := rconfig.defaults[key]
+	rconfig.writeFrom(&changes, key, value, SourceDefault)
+	return ok
+}
+
+// MarkSensitive flags keys whose values should be masked on output
+func (rconfig *RuntimeConfig) MarkSensitive(keys ...string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	for _, key := range keys {
+		rconfig.sensitive[rconfig.canonical(key)] = true
+	}
+}
+
+// MarkSensitivePattern flags every key matching the regular expression
+// pattern as sensitive, e.g. .*(TOKEN|SECRET|PASSWORD).*, this covers
+// keys added later as well as the current ones
+func (rconfig *RuntimeConfig) MarkSensitivePattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid sensitive pattern: %w", err)
+	}
+
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.sensitiveRes = append(rconfig.sensitiveRes, re)
+	return nil
+}
+
+// isSensitive reports whether key was marked sensitive by name or by
+// pattern, callers must hold the lock
+func (rconfig *RuntimeConfig) isSensitive(key string) bool {
+	if rconfig.sensitive[key] {
+		return true
+	}
+	for _, re := range rconfig.sensitiveRes {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAllowedValues restricts key to one of values
+func (rconfig *RuntimeConfig) SetAllowedValues(key string, values ...string) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.allowed[rconfig.canonical(key)] = append([]string(nil), values...)
+}
+
+// SetPattern requires the value of key to match the regular expression
+// pattern, an error is returned if pattern does not compile
+func (rconfig *RuntimeConfig) SetPattern(key, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern for key '%s': %w", key, err)
+	}
+
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.patterns[rconfig.canonical(key)] = re
+	return nil
+}
+
+// lengthRange bounds the rune length of a value, a max of 0 is unbounded
+type lengthRange struct {
+	min, max int
+}
+
+// String returns the bounds as min-max, or min+ when unbounded
+func (r lengthRange) String() string {
+	if r.max == 0 {
+		return fmt.Sprintf("%d+", r.min)
+	}
+	return fmt.Sprintf("%d-%d", r.min, r.max)
+}
+
+// SetLengthConstraint requires the value of key to be between min and
+// max runes long, a max of 0 leaves the length unbounded
+func (rconfig *RuntimeConfig) SetLengthConstraint(key string, min, max int) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.lengths[rconfig.canonical(key)] = lengthRange{min: min, max: max}
+}
+
+// AddValidator attaches a custom check to key, validators run in the
+// order they were added
+func (rconfig *RuntimeConfig) AddValidator(key string, fn func(value string) error) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	rconfig.validators[key] = append(rconfig.validators[key], fn)
+}
+
+// SetStrictValidation toggles running the per key constraints in Set,
+// invalid values are then rejected instead of stored
+func (rconfig *RuntimeConfig) SetStrictValidation(on bool) {
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	rconfig.strict = on
+}
+
+// SetChecked assigns a key value pair after running the per key
+// constraints, an invalid value is not stored and its error returned
+func (rconfig *RuntimeConfig) SetChecked(key, value string) error {
+	var changes []change
+	defer rconfig.notify(&changes)
+	rconfig.mu.Lock()
+	defer rconfig.mu.Unlock()
+	key = rconfig.canonical(key)
+	if err := rconfig.checkCapacity(key); err != nil {
+		return err
+	}
+	if value != "" {
+		if err := rconfig.checkValue(key, value); err != nil {
+			return err
+		}
+	}
+	rconfig.write(&changes, key, value)
+	return nil
+}
+
+// ValidateAll checks every registered constraint against the current
+// values and returns all failures joined into a single error
+// note: ignored and derived keys are not checked for a missing value
+func (rconfig *RuntimeConfig) ValidateAll() error {
+	rconfig.mu.RLock()
+	defer rconfig.mu.RUnlock()
+	return rconfig.validate()
+}
+
+// validate implements ValidateAll, callers must hold the lock
+func (rconfig *RuntimeConfig) validate() error {
+	var errs []error
+	for _, key := range sortedKeys(rconfig.data) {
+		value := rconfig.data[key]
+		if rconfig.isEmpty(value) {
+			if rconfig.required[key] && !rconfig.exempt(key) {
+				errs = append(errs, fmt.Errorf("key '%s' is required", key))
+			}
+			continue
+		}
+		if err := rconfig.checkValue(key, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, key := range sortedKeys(rconfig.requiredIf) {
+		if !rconfig.isEmpty(rconfig.data[key]) || rconfig.exempt(key) {
+			continue
+		}
+		for _, c := range rconfig.requiredIf[key] {
+			if rconfig.data[c.key] == c.value {
+				errs = append(errs, fmt.Errorf("key '%s' is required when %s=%s", key, c.key, c.value))
+				break
+			}
+		}
+	}
+
+	for _, group := range rconfig.exclusive {
+		var set []string
+		for _, key := range group {
+			if !rconfig.isEmpty(rconfig.data[key]) {
+				set = append(set, key)
+			}
+		}
+		if len(set) > 1 {
+			errs = append(errs, fmt.Errorf("mutually exclusive keys set together: '%s'", strings.Join(set, "', '")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkValue runs the per key constraints against a non-empty value,
+// callers must hold the lock
+func (rconfig *RuntimeConfig) checkValue(key, value string) error {
+	if err := rconfig.checkType(key, value); err != nil {
+		return err
+	}
+	if allowed, ok := rconfig.allowed[key]; ok && !slices.Contains(allowed, value) {
+		return fmt.Errorf("key '%s' must be one of %s", key, strings.Join(allowed, ","))
+	}
+	if re, ok := rconfig.patterns[key]; ok && !re.MatchString(value) {
+		return fmt.Errorf("key '%s' does not match pattern %s", key, re)
+	}
+	if r, ok := rconfig.lengths[key]; ok {
+		n := utf8.RuneCountInString(value)
+		if n < r.min || (r.max > 0 && n > r.max) {
+			return fmt.Errorf("key '%s' has length %d, expected %s", key, n, r)
+		}
+	}
+	for _, fn := range rconfig.validators[key] {
+		if err := fn(value); err != nil {
+			return fmt.Errorf("key '%s' is invalid: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package runtimeconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetMutuallyExclusive(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr bool
+	}{
+		{"none set", map[string]string{}, false},
+		{"one set", map[string]string{"TOKEN": "t"}, false},
+		{"two set", map[string]string{"TOKEN": "t", "PASSWORD": "p"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"TOKEN", "PASSWORD", "USER"}, nil)
+			rc.SetMutuallyExclusive("TOKEN", "PASSWORD")
+			for key, value := range tt.values {
+				rc.Set(key, value)
+			}
+
+			err := rc.ValidateAll()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "'TOKEN', 'PASSWORD'") {
+				t.Errorf("ValidateAll() error = %q, want it to name both keys", err)
+			}
+		})
+	}
+}
+
+func TestResetToDefault(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "HOST"}, nil)
+	rc.SetDefault("PORT", "8080")
+	rc.Set("PORT", "9090")
+	rc.Set("HOST", "example.com")
+
+	if !rc.ResetToDefault("PORT") {
+		t.Error("ResetToDefault(PORT) = false, want true")
+	}
+	if got := rc.Get("PORT"); got != "8080" {
+		t.Errorf("Get(PORT) = %q, want the default %q", got, "8080")
+	}
+
+	if rc.ResetToDefault("HOST") {
+		t.Error("ResetToDefault(HOST) = true without a default")
+	}
+	if got := rc.Get("HOST"); got != "" {
+		t.Errorf("Get(HOST) = %q, want empty", got)
+	}
+}
+
+func TestSetChecked(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"MODE"}, nil)
+	rc.SetAllowedValues("MODE", "dev", "prod")
+
+	if err := rc.SetChecked("MODE", "staging"); err == nil {
+		t.Error("SetChecked() with a disallowed value returned no error")
+	}
+	if got := rc.Get("MODE"); got != "" {
+		t.Errorf("Get(MODE) = %q after a rejected SetChecked, want empty", got)
+	}
+
+	if err := rc.SetChecked("MODE", "prod"); err != nil {
+		t.Errorf("SetChecked() error = %v", err)
+	}
+	if got := rc.Get("MODE"); got != "prod" {
+		t.Errorf("Get(MODE) = %q, want %q", got, "prod")
+	}
+}
+
+func TestSetRequiredIf(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage string
+		bucket  string
+		wantErr bool
+	}{
+		{"met and set", "s3", "my-bucket", false},
+		{"met and unset", "s3", "", true},
+		{"not met and set", "disk", "my-bucket", false},
+		{"not met and unset", "disk", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"STORAGE", "S3_BUCKET"}, nil)
+			rc.SetRequiredIf("S3_BUCKET", "STORAGE", "s3")
+			rc.Set("STORAGE", tt.storage)
+			rc.Set("S3_BUCKET", tt.bucket)
+			if err := rc.ValidateAll(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMarkSensitivePattern(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"API_TOKEN", "DB_PASSWORD", "HOST"}, nil)
+	if err := rc.MarkSensitivePattern(`.*(TOKEN|SECRET|PASSWORD).*`); err != nil {
+		t.Fatalf("MarkSensitivePattern() error = %v", err)
+	}
+	rc.Set("API_TOKEN", "t")
+	rc.Set("DB_PASSWORD", "p")
+	rc.Set("HOST", "h")
+	rc.Set("CLIENT_SECRET", "s") // added after the pattern
+
+	want := []string{"API_TOKEN=********", "CLIENT_SECRET=********", "DB_PASSWORD=********", "HOST=h"}
+	if got := rc.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+
+	if err := rc.MarkSensitivePattern(`(`); err == nil {
+		t.Error("MarkSensitivePattern() with a bad pattern returned no error")
+	}
+}
+
+func TestSetLengthConstraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"short", "ab", true},
+		{"min", "abc", false},
+		{"in range", "héllo", false},
+		{"max", "abcdefgh", false},
+		{"long", "abcdefghi", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRuntimeConfig([]string{"NAME"}, nil)
+			rc.SetLengthConstraint("NAME", 3, 8)
+			if err := rc.SetChecked("NAME", tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("SetChecked(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package runtimeconfig
+
+import (
+	"strconv"
+	"time"
+)
+
+// Value is the raw value of a key captured at read time, its typed
+// accessors never fail and fall back to Default on a parse failure
+type Value struct {
+	key      string
+	raw      string
+	fallback string
+}
+
+// Value returns a Value wrapping the current value of key
+func (rconfig *RuntimeConfig) Value(key string) Value {
+	return Value{key: key, raw: rconfig.typedValue(key)}
+}
+
+// Key returns the key the Value was read from
+func (v Value) Key() string {
+	return v.key
+}
+
+// Default returns a copy of the Value that uses fallback when the raw
+// value is empty or can not be parsed
+func (v Value) Default(fallback string) Value {
+	v.fallback = fallback
+	return v
+}
+
+// String returns the raw value or the default when empty
+func (v Value) String() string {
+	if v.raw == "" {
+		return v.fallback
+	}
+	return v.raw
+}
+
+// Int returns the value as an int, or zero if neither the value nor
+// the default parse
+func (v Value) Int() int {
+	return valueAs(v, strconv.Atoi)
+}
+
+// Bool returns the value parsed with strconv.ParseBool, or false if
+// neither the value nor the default parse
+func (v Value) Bool() bool {
+	return valueAs(v, strconv.ParseBool)
+}
+
+// Duration returns the value parsed with time.ParseDuration, or zero if
+// neither the value nor the default parse
+func (v Value) Duration() time.Duration {
+	return valueAs(v, time.ParseDuration)
+}
+
+// valueAs parses the raw value of v, then its default, returning the
+// zero value of T when both fail
+func valueAs[T any](v Value, parse func(string) (T, error)) T {
+	if t, err := parse(v.raw); err == nil {
+		return t
+	}
+	if t, err := parse(v.fallback); err == nil {
+		return t
+	}
+	var zero T
+	return zero
+}
@@ -0,0 +1,34 @@
+package runtimeconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValue(t *testing.T) {
+	rc := NewRuntimeConfig([]string{"PORT", "DEBUG", "TIMEOUT", "BAD", "EMPTY"}, nil)
+	rc.Set("PORT", "8080")
+	rc.Set("DEBUG", "true")
+	rc.Set("TIMEOUT", "5s")
+	rc.Set("BAD", "nope")
+
+	if v := rc.Value("PORT"); v.Key() != "PORT" || v.Int() != 8080 || v.String() != "8080" {
+		t.Errorf("Value(PORT) = %q %q %d, want PORT 8080 8080", v.Key(), v.String(), v.Int())
+	}
+	if !rc.Value("DEBUG").Bool() {
+		t.Error("Value(DEBUG).Bool() = false, want true")
+	}
+	if got := rc.Value("TIMEOUT").Duration(); got != 5*time.Second {
+		t.Errorf("Value(TIMEOUT).Duration() = %v, want 5s", got)
+	}
+
+	if got := rc.Value("EMPTY").Default("fallback").String(); got != "fallback" {
+		t.Errorf("Value(EMPTY).Default().String() = %q, want %q", got, "fallback")
+	}
+	if got := rc.Value("BAD").Default("3").Int(); got != 3 {
+		t.Errorf("Value(BAD).Default(3).Int() = %d, want 3", got)
+	}
+	if got := rc.Value("BAD").Int(); got != 0 {
+		t.Errorf("Value(BAD).Int() = %d, want 0", got)
+	}
+}